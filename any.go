@@ -0,0 +1,86 @@
+package wkttoorb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"unicode"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/pkg/errors"
+)
+
+// Format is an encoding of geometries detected by ScanAny
+type Format string
+
+const (
+	WKT     Format = "wkt"
+	EWKT    Format = "ewkt"
+	HexWKB  Format = "hexwkb"
+	WKB     Format = "wkb"
+	GeoJSON Format = "geojson"
+)
+
+// ScanAny parses data in whichever format it is encoded and returns the detected format
+// WKT starts with a geometry keyword, EWKT with SRID=, hex WKB with the hex digits
+// 00 or 01, WKB with the bytes 0 or 1 and GeoJSON with {
+// leading white space is ignored, WKB and hex WKB may carry an EWKB SRID
+func ScanAny(data []byte) (orb.Geometry, Format, error) {
+	g, _, format, err := scanAny(data)
+	return g, format, err
+}
+
+// scanAny is ScanAny also returning the SRID of EWKT and EWKB inputs
+func scanAny(data []byte) (orb.Geometry, int, Format, error) {
+	text := bytes.TrimLeftFunc(data, unicode.IsSpace)
+	switch {
+	case len(data) > 0 && (data[0] == 0 || data[0] == 1):
+		g, srid, err := decodeWKB(data)
+		if err != nil {
+			return nil, 0, WKB, errors.Wrap(err, "invalid wkb")
+		}
+		return g, srid, WKB, nil
+	case len(text) == 0:
+		return nil, 0, "", fmt.Errorf("empty input")
+	case text[0] == '{':
+		g, err := geojson.UnmarshalGeometry(text)
+		if err != nil {
+			return nil, 0, GeoJSON, errors.Wrap(err, "invalid geojson")
+		}
+		return g.Geometry(), 0, GeoJSON, nil
+	case isHexWKB(text):
+		b := make([]byte, hex.DecodedLen(len(bytes.TrimSpace(text))))
+		if _, err := hex.Decode(b, bytes.TrimSpace(text)); err != nil {
+			return nil, 0, HexWKB, errors.Wrap(err, "invalid hex wkb")
+		}
+		g, srid, err := decodeWKB(b)
+		if err != nil {
+			return nil, 0, HexWKB, errors.Wrap(err, "invalid wkb")
+		}
+		return g, srid, HexWKB, nil
+	case len(text) >= 5 && bytes.EqualFold(text[:5], []byte("srid=")):
+		p := NewParser(bytes.NewReader(text))
+		g, err := p.Parse()
+		return g, p.SRID(), EWKT, err
+	case unicode.IsLetter(rune(text[0])):
+		g, err := Scan(string(text))
+		return g, 0, WKT, err
+	default:
+		return nil, 0, "", fmt.Errorf("unknown format starting with %q", text[0])
+	}
+}
+
+// isHexWKB returns true if text is made of hex digits starting with a byte order of 00 or 01
+func isHexWKB(text []byte) bool {
+	text = bytes.TrimSpace(text)
+	if len(text) < 2 || text[0] != '0' || (text[1] != '0' && text[1] != '1') {
+		return false
+	}
+	for _, c := range text {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package wkttoorb
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_scanAny(t *testing.T) {
+	pointWKB, _ := hex.DecodeString("0101000000000000000000F03F0000000000000040")
+	inputs := [][]byte{
+		[]byte("POINT (1 2)"),
+		[]byte("  srid=4326;POINT (1 2)"),
+		[]byte("0101000000000000000000F03F0000000000000040"),
+		[]byte("0101000020E6100000000000000000F03F0000000000000040\n"),
+		[]byte("01E9030000000000000000F03F00000000000000400000000000000840"),
+		[]byte("0101000080000000000000F03F00000000000000400000000000000840"),
+		pointWKB,
+		[]byte(`{"type": "Point", "coordinates": [1, 2]}`),
+		[]byte("\tlinestring(1 2, 3 4)"),
+	}
+	formats := []Format{WKT, EWKT, HexWKB, HexWKB, HexWKB, HexWKB, WKB, GeoJSON, WKT}
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {3, 4}},
+	}
+
+	for i, data := range inputs {
+		g, format, err := ScanAny(data)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if format != formats[i] {
+			t.Errorf("incorrect format returned on test %d: %s", i, format)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_scanAnyInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"   ",
+		"[1, 2]",
+		"01zz",
+		"{\"type\": \"Nope\"}",
+		"0100",
+		"0101000000000000000000F03F000000000000004000",
+		"01B90B0000000000000000F03F0000000000000040",
+		"\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\x00\x40\x01",
+	}
+
+	for i, str := range inputs {
+		if _, _, err := ScanAny([]byte(str)); err == nil {
+			t.Errorf("expected an error on test %d", i)
+		}
+	}
+}
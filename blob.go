@@ -0,0 +1,179 @@
+package wkttoorb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/pkg/errors"
+)
+
+// Geometry is a geometry read from a database along with its SRID
+// it implements sql.Scanner for columns holding WKT, EWKT, WKB, EWKB,
+// GeoPackage, SpatiaLite or MySQL geometries
+type Geometry struct {
+	Geometry orb.Geometry
+	SRID     int
+	// Envelope is the bound stored in the GeoPackage and SpatiaLite headers
+	// or the bound of the geometry for the other formats
+	Envelope orb.Bound
+	// Valid is false if the column is NULL
+	Valid bool
+}
+
+// Scan implements sql.Scanner
+func (g *Geometry) Scan(src interface{}) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		*g = Geometry{}
+		return nil
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	default:
+		return fmt.Errorf("cannot scan %T into a geometry", src)
+	}
+
+	var err error
+	switch {
+	case isGeoPackage(data):
+		*g, err = DecodeGeoPackage(data)
+	case isSpatiaLite(data):
+		*g, err = DecodeSpatiaLite(data)
+	default:
+		mysql, ok := tryDecodeMySQL(data)
+		if ok {
+			*g = mysql
+			break
+		}
+		*g = Geometry{}
+		g.Geometry, g.SRID, _, err = scanAny(data)
+		if err == nil {
+			g.Envelope = g.Geometry.Bound()
+		}
+	}
+	if err != nil {
+		*g = Geometry{}
+		return err
+	}
+	g.Valid = true
+	return nil
+}
+
+// GeoPackage binary header flags
+const (
+	gpkgLittleEndian = 1
+	gpkgEnvelopeMask = 0x0e
+	gpkgEmpty        = 1 << 4
+	gpkgExtended     = 1 << 5
+)
+
+func isGeoPackage(data []byte) bool {
+	return len(data) >= 8 && data[0] == 'G' && data[1] == 'P'
+}
+
+// DecodeGeoPackage decodes a GeoPackage geometry blob, a GP header holding
+// the SRID and an optional envelope followed by a WKB geometry
+// the Z and M values are skipped, extended geometries are not supported
+func DecodeGeoPackage(data []byte) (Geometry, error) {
+	if !isGeoPackage(data) {
+		return Geometry{}, fmt.Errorf("invalid geopackage blob, missing GP magic")
+	}
+	if data[2] != 0 {
+		return Geometry{}, fmt.Errorf("unsupported geopackage version %d", data[2])
+	}
+	flags := data[3]
+	if flags&gpkgExtended != 0 {
+		return Geometry{}, fmt.Errorf("unsupported extended geopackage geometry")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if flags&gpkgLittleEndian != 0 {
+		order = binary.LittleEndian
+	}
+	g := Geometry{SRID: int(int32(order.Uint32(data[4:8])))}
+
+	// the envelope is minx, maxx, miny, maxy followed by the Z and M ranges
+	var n int
+	switch envelope := (flags & gpkgEnvelopeMask) >> 1; envelope {
+	case 0:
+	case 1:
+		n = 4
+	case 2, 3:
+		n = 6
+	case 4:
+		n = 8
+	default:
+		return Geometry{}, fmt.Errorf("invalid geopackage envelope indicator %d", envelope)
+	}
+	offset := 8 + 8*n
+	if len(data) < offset {
+		return Geometry{}, fmt.Errorf("invalid geopackage blob, %d bytes for a %d bytes header", len(data), offset)
+	}
+	if n > 0 {
+		v := func(i int) float64 {
+			return math.Float64frombits(order.Uint64(data[8+8*i:]))
+		}
+		g.Envelope = orb.Bound{Min: orb.Point{v(0), v(2)}, Max: orb.Point{v(1), v(3)}}
+	}
+
+	geom, _, err := decodeWKB(data[offset:])
+	if err != nil {
+		return Geometry{}, errors.Wrap(err, "invalid geopackage geometry")
+	}
+	g.Geometry = geom
+	if n == 0 && flags&gpkgEmpty == 0 {
+		g.Envelope = geom.Bound()
+	}
+	g.Valid = true
+	return g, nil
+}
+
+// SpatiaLite blob markers
+const (
+	spatiaLiteStart  = 0x00
+	spatiaLiteMBREnd = 0x7c
+	spatiaLiteEntity = 0x69
+	spatiaLiteEnd    = 0xfe
+)
+
+func isSpatiaLite(data []byte) bool {
+	return len(data) >= 44 && data[0] == spatiaLiteStart && data[38] == spatiaLiteMBREnd && data[len(data)-1] == spatiaLiteEnd
+}
+
+// DecodeSpatiaLite decodes a SpatiaLite geometry blob, a header holding
+// the SRID and the MBR followed by the geometry in a WKB like form
+// compressed geometries are not supported, Z and M values are skipped
+func DecodeSpatiaLite(data []byte) (Geometry, error) {
+	if !isSpatiaLite(data) {
+		return Geometry{}, fmt.Errorf("invalid spatialite blob, missing start, mbr or end marker")
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if data[1] == 1 {
+		order = binary.LittleEndian
+	}
+	v := func(i int) float64 {
+		return math.Float64frombits(order.Uint64(data[6+8*i:]))
+	}
+	g := Geometry{
+		SRID:     int(int32(order.Uint32(data[2:6]))),
+		Envelope: orb.Bound{Min: orb.Point{v(0), v(1)}, Max: orb.Point{v(2), v(3)}},
+	}
+
+	r := wkbReader{r: bytes.NewReader(data[39 : len(data)-1]), order: order, spatiaLite: true}
+	class, err := r.uint32()
+	if err != nil {
+		return Geometry{}, err
+	}
+	if g.Geometry, err = r.geometry(class); err != nil {
+		return Geometry{}, errors.Wrap(err, "invalid spatialite geometry")
+	}
+	if r.r.Len() > 0 {
+		return Geometry{}, fmt.Errorf("invalid spatialite blob, %d bytes after the geometry", r.r.Len())
+	}
+	g.Valid = true
+	return g, nil
+}
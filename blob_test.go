@@ -0,0 +1,192 @@
+package wkttoorb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
+)
+
+// geoPackageBlob returns g as a little endian GeoPackage blob with an XY envelope
+func geoPackageBlob(g orb.Geometry, srid int32) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{'G', 'P', 0, 0x03})
+	bound := g.Bound()
+	binary.Write(&b, binary.LittleEndian, srid)
+	binary.Write(&b, binary.LittleEndian, []float64{bound.Min[0], bound.Max[0], bound.Min[1], bound.Max[1]})
+	b.Write(wkb.MustMarshal(g, binary.LittleEndian))
+	return b.Bytes()
+}
+
+// spatiaLiteBlob returns the SpatiaLite blob of class made of the given body
+func spatiaLiteBlob(srid int32, bound orb.Bound, class uint32, body ...interface{}) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0x00, 0x01})
+	binary.Write(&b, binary.LittleEndian, srid)
+	binary.Write(&b, binary.LittleEndian, []float64{bound.Min[0], bound.Min[1], bound.Max[0], bound.Max[1]})
+	b.WriteByte(0x7c)
+	binary.Write(&b, binary.LittleEndian, class)
+	for _, v := range body {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteByte(0xfe)
+	return b.Bytes()
+}
+
+func Test_decodeGeoPackage(t *testing.T) {
+	polygon := orb.Polygon{{{0, 0}, {4, 0}, {4, 3}, {0, 0}}}
+	g, err := DecodeGeoPackage(geoPackageBlob(polygon, 4326))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := Geometry{Geometry: polygon, SRID: 4326, Envelope: polygon.Bound(), Valid: true}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect value returned %+v", g)
+	}
+
+	// no envelope, big endian header
+	var b bytes.Buffer
+	b.Write([]byte{'G', 'P', 0, 0x00, 0, 0, 0x0f, 0x6c})
+	b.Write(wkb.MustMarshal(orb.Point{1, 2}))
+	g, err = DecodeGeoPackage(b.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected = Geometry{Geometry: orb.Point{1, 2}, SRID: 3948, Envelope: orb.Point{1, 2}.Bound(), Valid: true}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect value returned %+v", g)
+	}
+
+	// ISO WKB, a ZM multipoint of a Z and a ZM point
+	b.Reset()
+	b.Write([]byte{'G', 'P', 0, 0x01, 0xe6, 0x10, 0, 0})
+	for _, v := range []interface{}{
+		byte(1), uint32(3004), uint32(2),
+		byte(1), uint32(1001), []float64{1, 2, 3},
+		byte(1), uint32(3001), []float64{5, 6, 7, 8},
+	} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	g, err = DecodeGeoPackage(b.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	mp := orb.MultiPoint{{1, 2}, {5, 6}}
+	expected = Geometry{Geometry: mp, SRID: 4326, Envelope: mp.Bound(), Valid: true}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect value returned %+v", g)
+	}
+}
+
+func Test_decodeGeoPackageInvalid(t *testing.T) {
+	point := wkb.MustMarshal(orb.Point{1, 2}, binary.LittleEndian)
+	inputs := [][]byte{
+		append([]byte{'G', 'P', 0, 0x01, 0, 0, 0, 0}, append(point, 0)...),
+		append([]byte{'G', 'P', 1, 0x01, 0, 0, 0, 0}, point...),
+		append([]byte{'G', 'P', 0, 0x21, 0, 0, 0, 0}, point...),
+		append([]byte{'G', 'P', 0, 0x0b, 0, 0, 0, 0}, point...),
+		{'G', 'P', 0, 0x01, 0, 0, 0, 0, 1, 0xb9, 0x0b, 0, 0, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f},
+	}
+
+	for i, data := range inputs {
+		if _, err := DecodeGeoPackage(data); err == nil {
+			t.Errorf("expected an error on test %d", i)
+		}
+	}
+}
+
+func Test_decodeSpatiaLite(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{5, 6}}
+	inputs := [][]byte{
+		spatiaLiteBlob(4326, bound, 1, []float64{1, 2}),
+		spatiaLiteBlob(4326, bound, 1002, uint32(2), []float64{1, 2, 9, 5, 6, 9}),
+		spatiaLiteBlob(4326, bound, 2001, []float64{1, 2, 9}),
+		spatiaLiteBlob(4326, bound, 3004, uint32(2),
+			byte(0x69), uint32(3001), []float64{1, 2, 8, 9},
+			byte(0x69), uint32(1001), []float64{5, 6, 8}),
+		spatiaLiteBlob(4326, bound, 3, uint32(1), uint32(4), []float64{1, 2, 5, 2, 5, 6, 1, 2}),
+		spatiaLiteBlob(4326, bound, 4, uint32(2),
+			byte(0x69), uint32(1), []float64{1, 2},
+			byte(0x69), uint32(1), []float64{5, 6}),
+		spatiaLiteBlob(4326, bound, 7, uint32(2),
+			byte(0x69), uint32(1), []float64{1, 2},
+			byte(0x69), uint32(2), uint32(2), []float64{1, 2, 5, 6}),
+	}
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {5, 6}},
+		orb.Point{1, 2},
+		orb.MultiPoint{{1, 2}, {5, 6}},
+		orb.Polygon{{{1, 2}, {5, 2}, {5, 6}, {1, 2}}},
+		orb.MultiPoint{{1, 2}, {5, 6}},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 2}, {5, 6}}},
+	}
+
+	for i, data := range inputs {
+		g, err := DecodeSpatiaLite(data)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		expected := Geometry{Geometry: outputs[i], SRID: 4326, Envelope: bound, Valid: true}
+		if !reflect.DeepEqual(g, expected) {
+			t.Errorf("incorrect value returned on test %d: %+v", i, g)
+		}
+	}
+}
+
+func Test_decodeSpatiaLiteInvalid(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{5, 6}}
+	inputs := [][]byte{
+		spatiaLiteBlob(4326, bound, 1, []float64{1}),
+		spatiaLiteBlob(4326, bound, 2, uint32(1000), []float64{1, 2}),
+		spatiaLiteBlob(4326, bound, 4, uint32(1), byte(0x42), uint32(1), []float64{1, 2}),
+		spatiaLiteBlob(4326, bound, 1000001, []float64{1, 2}),
+		spatiaLiteBlob(4326, bound, 1, []float64{1, 2, 3}),
+		spatiaLiteBlob(4326, bound, 3001, []float64{1, 2, 3}),
+		spatiaLiteBlob(4326, bound, 0x80000001, []float64{1, 2, 3}),
+	}
+
+	for i, data := range inputs {
+		if _, err := DecodeSpatiaLite(data); err == nil {
+			t.Errorf("expected an error on test %d", i)
+		}
+	}
+}
+
+func Test_geometryScan(t *testing.T) {
+	point := orb.Point{1, 2}
+	inputs := []interface{}{
+		"SRID=4326;POINT (1 2)",
+		[]byte("POINT (1 2)"),
+		wkb.MustMarshal(point),
+		geoPackageBlob(point, 2154),
+		spatiaLiteBlob(3857, point.Bound(), 1, []float64{1, 2}),
+		nil,
+	}
+	outputs := []Geometry{
+		{Geometry: point, SRID: 4326, Envelope: point.Bound(), Valid: true},
+		{Geometry: point, Envelope: point.Bound(), Valid: true},
+		{Geometry: point, Envelope: point.Bound(), Valid: true},
+		{Geometry: point, SRID: 2154, Envelope: point.Bound(), Valid: true},
+		{Geometry: point, SRID: 3857, Envelope: point.Bound(), Valid: true},
+		{},
+	}
+
+	for i, src := range inputs {
+		var g Geometry
+		if err := g.Scan(src); err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %+v", i, g)
+		}
+	}
+
+	var g Geometry
+	if err := g.Scan(42); err == nil {
+		t.Errorf("expected an error scanning an int")
+	}
+}
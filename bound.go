@@ -0,0 +1,57 @@
+package wkttoorb
+
+import (
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ScanBound returns the bound of the WKT geometry s without building it
+// a geometry without coordinates has an empty bound, see orb.Bound.IsEmpty
+func ScanBound(s string, opts ...Option) (orb.Bound, error) {
+	var h boundHandler
+	p := NewParser(strings.NewReader(s), opts...)
+	if err := p.ParseWith(&h); err != nil {
+		return orb.Bound{}, err
+	}
+	return h.bound, nil
+}
+
+// boundHandler is a Handler only computing the bound of the coordinates
+type boundHandler struct {
+	bound orb.Bound
+	depth int
+}
+
+func (h *boundHandler) BeginGeometry(gtype GeometryType, dims Dims) error {
+	if h.depth == 0 {
+		h.bound = orb.Bound{
+			Min: orb.Point{math.Inf(1), math.Inf(1)},
+			Max: orb.Point{math.Inf(-1), math.Inf(-1)},
+		}
+	}
+	h.depth++
+	return nil
+}
+
+func (h *boundHandler) BeginPart() error {
+	return nil
+}
+
+func (h *boundHandler) Coordinate(x, y, z, m float64) error {
+	h.bound.Min[0] = math.Min(h.bound.Min[0], x)
+	h.bound.Min[1] = math.Min(h.bound.Min[1], y)
+	h.bound.Max[0] = math.Max(h.bound.Max[0], x)
+	h.bound.Max[1] = math.Max(h.bound.Max[1], y)
+	return nil
+}
+
+func (h *boundHandler) EndPart() error {
+	return nil
+}
+
+func (h *boundHandler) EndGeometry() error {
+	h.depth--
+	return nil
+}
@@ -0,0 +1,103 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_scanBound(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"LINESTRING (1 2, -3 4, 5 -6)",
+		"POLYGON Z ((0 0 1, 10 0 1, 10 5 1, 0 0 1))",
+		"MULTIPOLYGON (((1 2, 3 4, 5 6, 1 2)), ((-1 2, 3 4, 5 16, -1 2)))",
+		"GEOMETRYCOLLECTION (POINT (7 8), LINESTRING (1 2, 3 4))",
+	}
+	outputs := []orb.Bound{
+		{Min: orb.Point{1, 2}, Max: orb.Point{1, 2}},
+		{Min: orb.Point{-3, -6}, Max: orb.Point{5, 4}},
+		{Min: orb.Point{0, 0}, Max: orb.Point{10, 5}},
+		{Min: orb.Point{-1, 2}, Max: orb.Point{5, 16}},
+		{Min: orb.Point{1, 2}, Max: orb.Point{7, 8}},
+	}
+
+	for i, str := range inputs {
+		bound, err := ScanBound(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if bound != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %v", i, bound)
+		}
+	}
+}
+
+func Test_scanBoundEmpty(t *testing.T) {
+	inputs := []string{
+		"POINT EMPTY",
+		"MULTIPOLYGON (EMPTY, EMPTY)",
+	}
+
+	for i, str := range inputs {
+		bound, err := ScanBound(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !bound.IsEmpty() {
+			t.Errorf("expected empty bound on test %d: %v", i, bound)
+		}
+	}
+}
+
+func Test_scanBoundAllocs(t *testing.T) {
+	lineString := func(n int) string {
+		var b strings.Builder
+		b.WriteString("LINESTRING (")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%d.25 -%d.5", i, i)
+		}
+		b.WriteString(")")
+		return b.String()
+	}
+	small, large := lineString(10), lineString(10000)
+
+	allocs := func(s string) float64 {
+		return testing.AllocsPerRun(10, func() {
+			if _, err := ScanBound(s); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+		})
+	}
+	if a, b := allocs(small), allocs(large); a != b {
+		t.Errorf("allocations grow with the number of vertices: %v for 10, %v for 10000", a, b)
+	}
+}
+
+func Test_scanBoundEmptyMembers(t *testing.T) {
+	inputs := []string{
+		"MULTIPOINT (EMPTY, 5 5)",
+		"MULTIPOINT ((1 2), EMPTY, (3 -4))",
+	}
+
+	for i, str := range inputs {
+		bound, err := ScanBound(str)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		geo, err := Scan(str)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if geo.Bound() != bound {
+			t.Errorf("incorrect bound on test %d: %v and %v", i, geo.Bound(), bound)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package wkttoorb
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// OrbBuilder is the Handler used by Parse, it builds orb geometries
+// Z and M values are dropped since orb does not support them
+// EMPTY members are kept as empty slices, and EMPTY points as orb.Point{0, 0}
+type OrbBuilder struct {
+	// stack holds the geometries being built, more than one for collections
+	stack []orbFrame
+
+	geom orb.Geometry
+}
+
+// orbFrame is the state of a geometry being built
+type orbFrame struct {
+	gtype GeometryType
+	depth int
+
+	point orb.Point
+	line  orb.LineString
+	poly  orb.Polygon
+	mls   orb.MultiLineString
+	mpoly orb.MultiPolygon
+	coll  orb.Collection
+}
+
+// Geometry returns the last geometry built
+func (b *OrbBuilder) Geometry() orb.Geometry {
+	return b.geom
+}
+
+func (b *OrbBuilder) top() *orbFrame {
+	return &b.stack[len(b.stack)-1]
+}
+
+func (b *OrbBuilder) BeginGeometry(gtype GeometryType, dims Dims) error {
+	b.stack = append(b.stack, orbFrame{
+		gtype: gtype,
+		line:  make(orb.LineString, 0),
+		poly:  make(orb.Polygon, 0),
+		mls:   make(orb.MultiLineString, 0),
+		mpoly: make(orb.MultiPolygon, 0),
+		coll:  make(orb.Collection, 0),
+	})
+	return nil
+}
+
+func (b *OrbBuilder) BeginPart() error {
+	f := b.top()
+	f.depth++
+	switch {
+	case f.gtype == TypeMultiPoint:
+	case f.gtype == TypeMultiPolygon && f.depth == 1:
+		f.poly = make(orb.Polygon, 0)
+	default:
+		f.line = make(orb.LineString, 0)
+	}
+	return nil
+}
+
+func (b *OrbBuilder) Coordinate(x, y, z, m float64) error {
+	f := b.top()
+	if f.gtype == TypePoint {
+		f.point = orb.Point{x, y}
+	} else {
+		f.line = append(f.line, orb.Point{x, y})
+	}
+	return nil
+}
+
+func (b *OrbBuilder) EndPart() error {
+	f := b.top()
+	switch {
+	case f.gtype == TypeMultiPoint:
+		// the point is added by Coordinate, an EMPTY point is dropped
+		// as orb has no empty point
+	case f.gtype == TypeMultiPolygon && f.depth == 1:
+		f.mpoly = append(f.mpoly, f.poly)
+	case f.gtype == TypeMultiLineString:
+		f.mls = append(f.mls, f.line)
+	default:
+		f.poly = append(f.poly, orb.Ring(f.line))
+	}
+	f.depth--
+	return nil
+}
+
+func (b *OrbBuilder) EndGeometry() error {
+	g := b.top().geometry()
+	b.stack = b.stack[:len(b.stack)-1]
+	if len(b.stack) > 0 {
+		f := b.top()
+		f.coll = append(f.coll, g)
+		return nil
+	}
+	b.geom = g
+	return nil
+}
+
+func (f *orbFrame) geometry() orb.Geometry {
+	switch f.gtype {
+	case TypePoint:
+		return f.point
+	case TypeLineString:
+		return f.line
+	case TypeMultiPoint:
+		return orb.MultiPoint(f.line)
+	case TypePolygon:
+		return f.poly
+	case TypeMultiLineString:
+		return f.mls
+	case TypeMultiPolygon:
+		return f.mpoly
+	default:
+		return f.coll
+	}
+}
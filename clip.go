@@ -0,0 +1,136 @@
+package wkttoorb
+
+import (
+	"math"
+	"strings"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/clip"
+)
+
+// ScanClipped parses the WKT string s keeping only its part within b
+// the points, lines and rings are clipped as they are parsed and the parts
+// outside of b are dropped without being built, a line crossing b several times
+// is split into several lines, nil is returned if the geometry is fully outside of b
+// as with orb/clip a multi geometry left with a single member is returned as that member
+func ScanClipped(s string, b orb.Bound, opts ...Option) (orb.Geometry, error) {
+	p := NewParser(strings.NewReader(s), opts...)
+	// the geometries are promoted so that a split line is a multi linestring
+	p.promote, p.clip = true, &b
+	g, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return unwrap(g), nil
+}
+
+// unwrap returns the single member of the multi geometries and collections of g
+// and nil for the empty ones
+func unwrap(g orb.Geometry) orb.Geometry {
+	switch g := g.(type) {
+	case orb.MultiPoint:
+		switch len(g) {
+		case 0:
+			return nil
+		case 1:
+			return g[0]
+		}
+	case orb.MultiLineString:
+		switch len(g) {
+		case 0:
+			return nil
+		case 1:
+			return g[0]
+		}
+	case orb.MultiPolygon:
+		switch len(g) {
+		case 0:
+			return nil
+		case 1:
+			return g[0]
+		}
+	case orb.Collection:
+		var c orb.Collection
+		for _, m := range g {
+			if m = unwrap(m); m != nil {
+				c = append(c, m)
+			}
+		}
+		switch len(c) {
+		case 0:
+			return nil
+		case 1:
+			return c[0]
+		}
+		return c
+	}
+	return g
+}
+
+// bound returns the bound of coords
+func bound(coords []coord) orb.Bound {
+	b := orb.Bound{Min: coords[0].point, Max: coords[0].point}
+	for _, c := range coords[1:] {
+		b = b.Extend(c.point)
+	}
+	return b
+}
+
+// clipLine returns the pieces of the line coords within b, nil if there is none
+// coords is returned as is if it is fully within b
+func (h *partFilter) clipLine(coords []coord) [][]coord {
+	b := bound(coords)
+	if !h.clip.Intersects(b) {
+		return nil
+	}
+	if h.clip.Contains(b.Min) && h.clip.Contains(b.Max) {
+		return [][]coord{coords}
+	}
+	mls := clip.LineString(*h.clip, h.scratch(coords))
+	pieces := make([][]coord, len(mls))
+	for i, ls := range mls {
+		pieces[i] = restoreZM(nil, coords, ls)
+	}
+	return pieces
+}
+
+// clipRing returns the ring coords clipped to b, nil if it is outside of b
+// coords is returned as is if it is fully within b
+func (h *partFilter) clipRing(coords []coord) []coord {
+	b := bound(coords)
+	if !h.clip.Intersects(b) {
+		return nil
+	}
+	if h.clip.Contains(b.Min) && h.clip.Contains(b.Max) {
+		return coords
+	}
+	r := clip.Ring(*h.clip, orb.Ring(h.scratch(coords)))
+	h.clipped = restoreZM(h.clipped[:0], coords, r)
+	return h.clipped
+}
+
+// scratch returns the points of coords in a buffer reused by the clipping
+func (h *partFilter) scratch(coords []coord) []orb.Point {
+	h.points = h.points[:0]
+	for _, c := range coords {
+		h.points = append(h.points, c.point)
+	}
+	return h.points
+}
+
+// restoreZM appends points to out as coordinates taking the Z and M values
+// of the matching coordinates of coords, the points added by the clipping have none
+func restoreZM(out []coord, coords []coord, points []orb.Point) []coord {
+	j := 0
+	for _, point := range points {
+		c := coord{point, math.NaN(), math.NaN()}
+		for k := j; k < len(coords); k++ {
+			if coords[k].point == point {
+				c, j = coords[k], k+1
+				break
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_scanClipped(t *testing.T) {
+	inputs := []string{
+		"POINT (20 20)",
+		"POINT (5 5)",
+		"LINESTRING (-5 5, 5 5)",
+		"LINESTRING (20 20, 30 30)",
+		"LINESTRING (-5 2, 5 2, 5 20, 8 20, 8 5)",
+		"POLYGON ((-5 -5, 5 -5, 5 5, -5 5, -5 -5))",
+		"POLYGON ((20 20, 30 20, 30 30, 20 20))",
+		"MULTIPOLYGON (((20 20, 30 20, 30 30, 20 20)), ((1 1, 2 1, 2 2, 1 1)))",
+		"MULTILINESTRING ((20 20, 30 30), (1 1, 2 2))",
+		"MULTIPOINT ((1 1), (20 20), (2 2))",
+		"MULTIPOINT ((20 20), (2 2))",
+		"GEOMETRYCOLLECTION (POINT (20 20), LINESTRING (-5 5, 5 5))",
+		"MULTILINESTRING ((-5 2, 5 2, 5 20, 8 20, 8 5), (1 1, 2 2))",
+		"POLYGON EMPTY",
+	}
+	outputs := []orb.Geometry{
+		nil,
+		orb.Point{5, 5},
+		orb.LineString{{0, 5}, {5, 5}},
+		nil,
+		orb.MultiLineString{{{0, 2}, {5, 2}, {5, 10}}, {{8, 10}, {8, 5}}},
+		orb.Polygon{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+		nil,
+		orb.Polygon{{{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		orb.LineString{{1, 1}, {2, 2}},
+		orb.MultiPoint{{1, 1}, {2, 2}},
+		orb.Point{2, 2},
+		orb.LineString{{0, 5}, {5, 5}},
+		orb.MultiLineString{{{0, 2}, {5, 2}, {5, 10}}, {{8, 10}, {8, 5}}, {{1, 1}, {2, 2}}},
+		nil,
+	}
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+
+	for i, str := range inputs {
+		g, err := ScanClipped(str, bound)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_scanClippedZ(t *testing.T) {
+	trace := &traceHandler{}
+	p := NewParser(strings.NewReader("LINESTRING Z (-5 5 1, 5 5 2, 6 6 3)"))
+	p.promote, p.clip = true, &orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+	if err := p.ParseWith(trace); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := []string{"begin MultiLineString 1", "(", "0 5 NaN NaN", "5 5 2 NaN", "6 6 3 NaN", ")", "end"}
+	if !reflect.DeepEqual(trace.events, expected) {
+		t.Errorf("incorrect events %v", trace.events)
+	}
+}
+
+func Test_scanClippedAllocs(t *testing.T) {
+	multiLineString := func(n int) string {
+		var b strings.Builder
+		b.WriteString("MULTILINESTRING (")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("(20 20, 30 30, 40 40)")
+		}
+		b.WriteString(", (1 1, 2 2))")
+		return b.String()
+	}
+	small, large := multiLineString(10), multiLineString(1000)
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}}
+
+	allocs := func(s string) float64 {
+		return testing.AllocsPerRun(10, func() {
+			if _, err := ScanClipped(s, bound); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+		})
+	}
+	if a, b := allocs(small), allocs(large); a != b {
+		t.Errorf("allocations grow with the discarded parts: %v for 10, %v for 1000", a, b)
+	}
+}
@@ -0,0 +1,45 @@
+package wkttoorb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// checkInterval is the number of coordinates parsed between two checks of the context
+const checkInterval = 1024
+
+// ScanContext is like Scan but stops early returning ctx.Err() when ctx is done
+func ScanContext(ctx context.Context, s string, opts ...Option) (orb.Geometry, error) {
+	p := NewParser(strings.NewReader(s), opts...)
+	return p.ParseContext(ctx)
+}
+
+// ParseContext is like Parse but stops early returning ctx.Err() when ctx is done
+func (p *Parser) ParseContext(ctx context.Context) (orb.Geometry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.ctx = ctx
+	defer func() { p.ctx = nil }()
+	return p.Parse()
+}
+
+// checkContext is called in the parsing loops
+// it only looks at the context every checkInterval calls to keep it cheap
+func (p *Parser) checkContext() error {
+	if p.ctx == nil {
+		return nil
+	}
+	p.ticks++
+	if p.ticks%checkInterval != 0 {
+		return nil
+	}
+	select {
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package wkttoorb
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_scanContext(t *testing.T) {
+	geo, err := ScanContext(context.Background(), "LINESTRING (1 2, 3 4)")
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(geo, orb.LineString{{1, 2}, {3, 4}}) {
+		t.Error("incorrect value returned")
+	}
+}
+
+func Test_scanContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ScanContext(ctx, "POINT (1 2)")
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled got %v", err)
+	}
+
+	coords := make([]string, 10*checkInterval)
+	for i := range coords {
+		coords[i] = "1 2"
+	}
+	p := NewParser(strings.NewReader("LINESTRING (" + strings.Join(coords, ",") + ")"))
+	p.ctx = ctx
+	_, err = p.Parse()
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled got %v", err)
+	}
+}
@@ -0,0 +1,368 @@
+// Package cql parses ECQL/CQL spatial filters such as
+// INTERSECTS(geom, POLYGON((...))) AND NOT BBOX(geom, 1, 2, 3, 4)
+// and evaluates them against orb geometries
+package cql
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// Op is the name of a spatial predicate
+type Op string
+
+const (
+	Intersects Op = "INTERSECTS"
+	Disjoint   Op = "DISJOINT"
+	Contains   Op = "CONTAINS"
+	Within     Op = "WITHIN"
+	Equals     Op = "EQUALS"
+	DWithin    Op = "DWITHIN"
+	Beyond     Op = "BEYOND"
+	BBOX       Op = "BBOX"
+)
+
+// unitFactors converts the distance units to meters
+var unitFactors = map[string]float64{
+	"meters":         1,
+	"kilometers":     1000,
+	"feet":           0.3048,
+	"statute miles":  1609.344,
+	"nautical miles": 1852,
+}
+
+// Properties returns the geometry of the named property of a feature
+// or nil if the feature does not have it
+type Properties func(name string) orb.Geometry
+
+// Filter is a node of a parsed filter
+type Filter interface {
+	// Eval returns true if the feature described by props matches the filter
+	// predicates on a missing property are false
+	Eval(props Properties) bool
+}
+
+// And matches features matching both its filters
+type And struct {
+	Left, Right Filter
+}
+
+// Or matches features matching one of its filters
+type Or struct {
+	Left, Right Filter
+}
+
+// Not matches features not matching its filter
+type Not struct {
+	Filter Filter
+}
+
+// Spatial is a predicate between a property and a geometry
+// EQUALS is topological, the geometries are equal if each contains the other
+// whatever the order and the number of their vertices
+type Spatial struct {
+	Op       Op
+	Property string
+	Geometry orb.Geometry
+}
+
+// Distance is a DWITHIN or BEYOND predicate
+// the coordinates are assumed to be planar and in meters
+type Distance struct {
+	Op       Op
+	Property string
+	Geometry orb.Geometry
+	Distance float64
+	Units    string
+}
+
+// BBox matches features whose property intersects the bound
+type BBox struct {
+	Property string
+	Bound    orb.Bound
+	// CRS is the optional crs argument, it is not used by Eval
+	CRS string
+}
+
+func (f And) Eval(props Properties) bool {
+	return f.Left.Eval(props) && f.Right.Eval(props)
+}
+
+func (f Or) Eval(props Properties) bool {
+	return f.Left.Eval(props) || f.Right.Eval(props)
+}
+
+func (f Not) Eval(props Properties) bool {
+	return !f.Filter.Eval(props)
+}
+
+func (f Spatial) Eval(props Properties) bool {
+	g := props(f.Property)
+	if g == nil {
+		return false
+	}
+	switch f.Op {
+	case Intersects:
+		return intersects(g, f.Geometry)
+	case Disjoint:
+		return !intersects(g, f.Geometry)
+	case Contains:
+		return contains(g, f.Geometry)
+	case Within:
+		return contains(f.Geometry, g)
+	case Equals:
+		return contains(g, f.Geometry) && contains(f.Geometry, g)
+	default:
+		return false
+	}
+}
+
+func (f Distance) Eval(props Properties) bool {
+	g := props(f.Property)
+	if g == nil {
+		return false
+	}
+	within := distance(g, f.Geometry) <= f.Distance*unitFactors[f.Units]
+	if f.Op == Beyond {
+		return !within
+	}
+	return within
+}
+
+func (f BBox) Eval(props Properties) bool {
+	g := props(f.Property)
+	if g == nil {
+		return false
+	}
+	return intersects(g, f.Bound)
+}
+
+// the predicates below are planar and based on the vertices and segments
+// of the geometries, they do not handle every degenerate case
+
+// intersects returns true if a and b share at least one point
+func intersects(a, b orb.Geometry) bool {
+	if !a.Bound().Intersects(b.Bound()) {
+		return false
+	}
+	for _, p := range vertices(a) {
+		if covers(b, p) {
+			return true
+		}
+	}
+	for _, p := range vertices(b) {
+		if covers(a, p) {
+			return true
+		}
+	}
+	for _, s := range segments(a) {
+		for _, t := range segments(b) {
+			if segmentsIntersect(s, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contains returns true if every vertex of b is in a
+// and no segment of b crosses the boundary of a
+func contains(a, b orb.Geometry) bool {
+	vs := vertices(b)
+	if len(vs) == 0 || !a.Bound().Contains(vs[0]) {
+		return false
+	}
+	for _, p := range vs {
+		if !covers(a, p) {
+			return false
+		}
+	}
+	for _, s := range segments(a) {
+		for _, t := range segments(b) {
+			if segmentsCross(s, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// distance returns the minimum distance between a and b
+func distance(a, b orb.Geometry) float64 {
+	if intersects(a, b) {
+		return 0
+	}
+	d := -1.0
+	for _, p := range vertices(a) {
+		if pd := planar.DistanceFrom(b, p); d < 0 || pd < d {
+			d = pd
+		}
+	}
+	for _, p := range vertices(b) {
+		if pd := planar.DistanceFrom(a, p); d < 0 || pd < d {
+			d = pd
+		}
+	}
+	return d
+}
+
+// covers returns true if p is in the interior or on the boundary of g
+func covers(g orb.Geometry, p orb.Point) bool {
+	switch g := g.(type) {
+	case orb.Point:
+		return g == p
+	case orb.MultiPoint:
+		for _, q := range g {
+			if q == p {
+				return true
+			}
+		}
+		return false
+	case orb.Polygon:
+		// orb panics on an EMPTY polygon or exterior ring
+		if len(g) == 0 || len(g[0]) == 0 {
+			return false
+		}
+		return planar.PolygonContains(g, p) || onSegments(g, p)
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			if covers(poly, p) {
+				return true
+			}
+		}
+		return false
+	case orb.Bound:
+		return g.Contains(p)
+	case orb.Collection:
+		for _, m := range g {
+			if covers(m, p) {
+				return true
+			}
+		}
+		return false
+	default:
+		return onSegments(g, p)
+	}
+}
+
+func onSegments(g orb.Geometry, p orb.Point) bool {
+	for _, s := range segments(g) {
+		if planar.DistanceFromSegmentSquared(s[0], s[1], p) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// vertices returns the points of g
+func vertices(g orb.Geometry) []orb.Point {
+	switch g := g.(type) {
+	case orb.Point:
+		return []orb.Point{g}
+	case orb.MultiPoint:
+		return g
+	case orb.LineString:
+		return g
+	case orb.Ring:
+		return g
+	case orb.Bound:
+		return g.ToRing()
+	case orb.Collection:
+		var ps []orb.Point
+		for _, m := range g {
+			ps = append(ps, vertices(m)...)
+		}
+		return ps
+	case orb.MultiLineString:
+		var ps []orb.Point
+		for _, l := range g {
+			ps = append(ps, l...)
+		}
+		return ps
+	case orb.Polygon:
+		var ps []orb.Point
+		for _, r := range g {
+			ps = append(ps, r...)
+		}
+		return ps
+	case orb.MultiPolygon:
+		var ps []orb.Point
+		for _, poly := range g {
+			ps = append(ps, vertices(poly)...)
+		}
+		return ps
+	default:
+		return nil
+	}
+}
+
+// segments returns the segments of the lines and rings of g
+func segments(g orb.Geometry) [][2]orb.Point {
+	var lines []orb.LineString
+	switch g := g.(type) {
+	case orb.LineString:
+		lines = []orb.LineString{g}
+	case orb.Ring:
+		lines = []orb.LineString{orb.LineString(g)}
+	case orb.Bound:
+		lines = []orb.LineString{orb.LineString(g.ToRing())}
+	case orb.MultiLineString:
+		lines = g
+	case orb.Polygon:
+		for _, r := range g {
+			lines = append(lines, orb.LineString(r))
+		}
+	case orb.MultiPolygon:
+		for _, poly := range g {
+			for _, r := range poly {
+				lines = append(lines, orb.LineString(r))
+			}
+		}
+	case orb.Collection:
+		var segs [][2]orb.Point
+		for _, m := range g {
+			segs = append(segs, segments(m)...)
+		}
+		return segs
+	}
+
+	var segs [][2]orb.Point
+	for _, l := range lines {
+		for i := 1; i < len(l); i++ {
+			segs = append(segs, [2]orb.Point{l[i-1], l[i]})
+		}
+	}
+	return segs
+}
+
+// orientation returns the sign of the cross product of ab and ac
+func orientation(a, b, c orb.Point) int {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// segmentsCross returns true if s and t intersect in a single point
+// interior to both of them
+func segmentsCross(s, t [2]orb.Point) bool {
+	o1, o2 := orientation(s[0], s[1], t[0]), orientation(s[0], s[1], t[1])
+	o3, o4 := orientation(t[0], t[1], s[0]), orientation(t[0], t[1], s[1])
+	return o1*o2 < 0 && o3*o4 < 0
+}
+
+// segmentsIntersect returns true if s and t share at least one point
+func segmentsIntersect(s, t [2]orb.Point) bool {
+	if segmentsCross(s, t) {
+		return true
+	}
+	return planar.DistanceFromSegmentSquared(s[0], s[1], t[0]) == 0 ||
+		planar.DistanceFromSegmentSquared(s[0], s[1], t[1]) == 0 ||
+		planar.DistanceFromSegmentSquared(t[0], t[1], s[0]) == 0 ||
+		planar.DistanceFromSegmentSquared(t[0], t[1], s[1]) == 0
+}
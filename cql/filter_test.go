@@ -0,0 +1,100 @@
+package cql
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_eval(t *testing.T) {
+	features := map[string]orb.Geometry{
+		"square": orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		"line":   orb.LineString{{-5, 5}, {15, 5}},
+		"point":  orb.Point{20, 20},
+		"multi":  orb.MultiPolygon{{}, {{{0, 0}, {10, 0}, {10, 10}, {0, 0}}}},
+		"coll":   orb.Collection{orb.Polygon{}, orb.Point{6, 7}},
+	}
+	props := func(name string) orb.Geometry {
+		return features[name]
+	}
+
+	inputs := []string{
+		"INTERSECTS(square, POINT(5 5))",
+		"INTERSECTS(square, POINT(10 5))",
+		"INTERSECTS(square, POINT(11 5))",
+		"INTERSECTS(line, LINESTRING(5 0, 5 10))",
+		"INTERSECTS(line, POLYGON((0 0, 1 0, 1 1, 0 0)))",
+		"DISJOINT(point, POLYGON((0 0, 10 0, 10 10, 0 0)))",
+		"CONTAINS(square, LINESTRING(1 1, 9 9))",
+		"CONTAINS(square, LINESTRING(1 1, 11 11))",
+		"WITHIN(square, POLYGON((-1 -1, 11 -1, 11 11, -1 11, -1 -1)))",
+		"WITHIN(line, POLYGON((-1 -1, 11 -1, 11 11, -1 11, -1 -1)))",
+		"EQUALS(point, POINT(20 20))",
+		"DWITHIN(point, POINT(23 24), 5, meters)",
+		"DWITHIN(point, POINT(23 24), 4.9, meters)",
+		"DWITHIN(square, POINT(13 14), 0.01, kilometers)",
+		"BEYOND(square, POINT(13 14), 1, feet)",
+		"BBOX(point, 19, 19, 21, 21)",
+		"BBOX(square, 11, 11, 21, 21)",
+		"BBOX(line, 2, 2, 3, 8)",
+		"INTERSECTS(missing, POINT(1 2))",
+		"NOT INTERSECTS(point, POINT(1 2)) AND (BBOX(line, 0, 0, 1, 1) OR EQUALS(point, POINT(20 20)))",
+		"INTERSECTS(square, GEOMETRYCOLLECTION(POINT(50 50), POINT(1 1)))",
+		"EQUALS(line, LINESTRING(15 5, -5 5))",
+		"EQUALS(line, LINESTRING(-5 5, 5 5, 15 5))",
+		"EQUALS(line, LINESTRING(-5 5, 10 5))",
+		"EQUALS(square, POLYGON((10 10, 0 10, 0 0, 10 0, 10 10)))",
+		"EQUALS(square, POLYGON((0 0, 10 0, 10 10, 0 0)))",
+		"INTERSECTS(multi, POINT(6 5))",
+		"INTERSECTS(multi, MULTIPOLYGON(EMPTY, ((0 0, 10 0, 10 10, 0 0))))",
+		"INTERSECTS(point, MULTIPOLYGON(EMPTY, ((0 0, 10 0, 10 10, 0 0))))",
+		"INTERSECTS(coll, GEOMETRYCOLLECTION(POLYGON EMPTY, POINT(6 7)))",
+		"CONTAINS(coll, POINT(6 7))",
+		"WITHIN(point, POLYGON EMPTY)",
+	}
+	outputs := []bool{
+		true,
+		true,
+		false,
+		true,
+		false,
+		true,
+		true,
+		false,
+		true,
+		false,
+		true,
+		true,
+		false,
+		true,
+		true,
+		true,
+		false,
+		true,
+		false,
+		true,
+		true,
+		true,
+		true,
+		false,
+		true,
+		false,
+		true,
+		true,
+		false,
+		true,
+		true,
+		false,
+	}
+
+	for i, str := range inputs {
+		f, err := Parse(str)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+			continue
+		}
+		if f.Eval(props) != outputs[i] {
+			t.Errorf("incorrect value returned on test %d", i)
+		}
+	}
+}
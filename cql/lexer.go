@@ -0,0 +1,108 @@
+package cql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	leftParen tokenType = iota
+	rightParen
+	comma
+
+	ident
+	number
+	str
+
+	eof
+)
+
+type token struct {
+	ttype  tokenType
+	lexeme string
+	pos    int
+}
+
+// lexer scans the tokens of a filter string
+// the parser reads geometry literals directly from s at pos
+type lexer struct {
+	s   string
+	pos int
+}
+
+// skipSpace moves pos after the white space
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.s) {
+		r, size := utf8.DecodeRuneInString(l.s[l.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// scan returns the next token
+func (l *lexer) scan() (token, error) {
+	l.skipSpace()
+	start := l.pos
+	if l.pos >= len(l.s) {
+		return token{eof, "", start}, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(l.s[l.pos:])
+	switch {
+	case r == '(':
+		l.pos += size
+		return token{leftParen, "(", start}, nil
+	case r == ')':
+		l.pos += size
+		return token{rightParen, ")", start}, nil
+	case r == ',':
+		l.pos += size
+		return token{comma, ",", start}, nil
+	case r == '\'' || r == '"':
+		end := strings.IndexRune(l.s[l.pos+size:], r)
+		if end < 0 {
+			return token{}, fmt.Errorf("unclosed string on pos %d", start)
+		}
+		l.pos += size + end + size
+		ttype := str
+		if r == '"' {
+			ttype = ident
+		}
+		return token{ttype, l.s[start+size : l.pos-size], start}, nil
+	case unicode.IsLetter(r) || r == '_':
+		l.pos = l.scanWhile(isIdentRune)
+		return token{ident, l.s[start:l.pos], start}, nil
+	case r == '-' || r == '+' || r == '.' || unicode.IsDigit(r):
+		l.pos = l.scanWhile(isNumberRune)
+		return token{number, l.s[start:l.pos], start}, nil
+	default:
+		return token{}, fmt.Errorf("unexpected rune %s on pos %d", string(r), start)
+	}
+}
+
+// scanWhile returns the position of the first rune after pos not matching f
+func (l *lexer) scanWhile(f func(rune) bool) int {
+	pos := l.pos
+	for pos < len(l.s) {
+		r, size := utf8.DecodeRuneInString(l.s[pos:])
+		if !f(r) {
+			break
+		}
+		pos += size
+	}
+	return pos
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == ':'
+}
+
+func isNumberRune(r rune) bool {
+	return unicode.IsDigit(r) || r == '-' || r == '+' || r == '.' || r == 'e' || r == 'E'
+}
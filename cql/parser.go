@@ -0,0 +1,276 @@
+package cql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Succo/wkttoorb"
+	"github.com/paulmach/orb"
+	"github.com/pkg/errors"
+)
+
+// Parse parses a filter made of spatial predicates combined with AND, OR and NOT
+// the supported predicates are
+//
+//	INTERSECTS, DISJOINT, CONTAINS, WITHIN, EQUALS (property, geometry)
+//	DWITHIN, BEYOND (property, geometry, distance, units)
+//	BBOX (property, minX, minY, maxX, maxY [, crs])
+//
+// geometries are WKT or EWKT literals read by wkttoorb
+func Parse(s string) (Filter, error) {
+	p := parser{lexer{s: s}}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	if t.ttype != eof {
+		return nil, fmt.Errorf("unexpected token %s on pos %d, expected end of filter", t.lexeme, t.pos)
+	}
+	return f, nil
+}
+
+type parser struct {
+	lexer
+}
+
+// parseOr parses filters separated by OR
+func (p *parser) parseOr() (Filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		f = Or{f, right}
+	}
+	return f, nil
+}
+
+// parseAnd parses filters separated by AND
+func (p *parser) parseAnd() (Filter, error) {
+	f, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		f = And{f, right}
+	}
+	return f, nil
+}
+
+// parseNot parses a filter optionally preceded by NOT
+func (p *parser) parseNot() (Filter, error) {
+	if p.acceptKeyword("not") {
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not{f}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a predicate or a filter between parens
+func (p *parser) parsePrimary() (Filter, error) {
+	t, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	if t.ttype == leftParen {
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(rightParen, "')'")
+	}
+	if t.ttype != ident {
+		return nil, fmt.Errorf("unexpected token %s on pos %d expected predicate", t.lexeme, t.pos)
+	}
+
+	op := Op(strings.ToUpper(t.lexeme))
+	switch op {
+	case Intersects, Disjoint, Contains, Within, Equals:
+		return p.parseSpatial(op)
+	case DWithin, Beyond:
+		return p.parseDistance(op)
+	case BBOX:
+		return p.parseBBox()
+	default:
+		return nil, fmt.Errorf("unknown predicate %s on pos %d", t.lexeme, t.pos)
+	}
+}
+
+func (p *parser) parseSpatial(op Op) (Filter, error) {
+	prop, g, err := p.parsePropertyGeometry()
+	if err != nil {
+		return nil, err
+	}
+	return Spatial{op, prop, g}, p.expect(rightParen, "')'")
+}
+
+func (p *parser) parseDistance(op Op) (Filter, error) {
+	prop, g, err := p.parsePropertyGeometry()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(comma, "','"); err != nil {
+		return nil, err
+	}
+	d, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(comma, "','"); err != nil {
+		return nil, err
+	}
+
+	// units may be made of several words as in statute miles
+	var words []string
+	for {
+		t, err := p.scan()
+		if err != nil {
+			return nil, err
+		}
+		if t.ttype == rightParen && len(words) > 0 {
+			break
+		}
+		if t.ttype != ident {
+			return nil, fmt.Errorf("unexpected token %s on pos %d expected units", t.lexeme, t.pos)
+		}
+		words = append(words, strings.ToLower(t.lexeme))
+	}
+	units := strings.Join(words, " ")
+	if _, ok := unitFactors[units]; !ok {
+		return nil, fmt.Errorf("unknown units %s", units)
+	}
+	return Distance{op, prop, g, d, units}, nil
+}
+
+func (p *parser) parseBBox() (Filter, error) {
+	if err := p.expect(leftParen, "'('"); err != nil {
+		return nil, err
+	}
+	prop, err := p.parseProperty()
+	if err != nil {
+		return nil, err
+	}
+	var v [4]float64
+	for i := range v {
+		if err := p.expect(comma, "','"); err != nil {
+			return nil, err
+		}
+		v[i], err = p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f := BBox{Property: prop, Bound: orb.MultiPoint{{v[0], v[1]}, {v[2], v[3]}}.Bound()}
+	t, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	if t.ttype == comma {
+		t, err = p.scan()
+		if err != nil {
+			return nil, err
+		}
+		if t.ttype != str {
+			return nil, fmt.Errorf("unexpected token %s on pos %d expected crs string", t.lexeme, t.pos)
+		}
+		f.CRS = t.lexeme
+		t, err = p.scan()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.ttype != rightParen {
+		return nil, fmt.Errorf("unexpected token %s on pos %d expected ')'", t.lexeme, t.pos)
+	}
+	return f, nil
+}
+
+// parsePropertyGeometry parses the opening paren and the two first arguments
+// of a predicate, a property name and a geometry literal
+func (p *parser) parsePropertyGeometry() (string, orb.Geometry, error) {
+	if err := p.expect(leftParen, "'('"); err != nil {
+		return "", nil, err
+	}
+	prop, err := p.parseProperty()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(comma, "','"); err != nil {
+		return "", nil, err
+	}
+
+	p.skipSpace()
+	g, n, err := wkttoorb.ParsePrefix(p.s[p.pos:])
+	if err != nil {
+		return "", nil, errors.Wrap(err, fmt.Sprintf("invalid geometry on pos %d", p.pos))
+	}
+	p.pos += n
+	return prop, g, nil
+}
+
+func (p *parser) parseProperty() (string, error) {
+	t, err := p.scan()
+	if err != nil {
+		return "", err
+	}
+	if t.ttype != ident {
+		return "", fmt.Errorf("unexpected token %s on pos %d expected property name", t.lexeme, t.pos)
+	}
+	return t.lexeme, nil
+}
+
+func (p *parser) parseNumber() (float64, error) {
+	t, err := p.scan()
+	if err != nil {
+		return 0, err
+	}
+	if t.ttype != number {
+		return 0, fmt.Errorf("unexpected token %s on pos %d expected number", t.lexeme, t.pos)
+	}
+	v, err := strconv.ParseFloat(t.lexeme, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("invalid number %s on pos %d", t.lexeme, t.pos))
+	}
+	return v, nil
+}
+
+// expect scans a token of type ttype, what describes it in the error message
+func (p *parser) expect(ttype tokenType, what string) error {
+	t, err := p.scan()
+	if err != nil {
+		return err
+	}
+	if t.ttype != ttype {
+		return fmt.Errorf("unexpected token %s on pos %d expected %s", t.lexeme, t.pos, what)
+	}
+	return nil
+}
+
+// acceptKeyword consumes the next token if it is the keyword kw
+func (p *parser) acceptKeyword(kw string) bool {
+	pos := p.pos
+	t, err := p.scan()
+	if err != nil || t.ttype != ident || !strings.EqualFold(t.lexeme, kw) {
+		p.pos = pos
+		return false
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package cql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_parse(t *testing.T) {
+	inputs := []string{
+		"INTERSECTS(geom, POLYGON((0 0, 10 0, 10 10, 0 0)))",
+		"dwithin(geom, POINT(1 2), 10, meters)",
+		"DWITHIN(the_geom, POINT (1 2), 1.5, statute miles)",
+		"BBOX(geom, 1, 2, 3, 4)",
+		"BBOX(geom, 1, 2, 3, 4, 'EPSG:4326')",
+		"WITHIN(\"a geom\", POINT(1 2)) AND NOT CONTAINS(geom, POINT(3 4))",
+		"EQUALS(a, POINT (1 2)) OR EQUALS(b, POINT (3 4)) AND DISJOINT(c, POINT EMPTY)",
+		"(EQUALS(a, POINT (1 2)) OR EQUALS(b, POINT (3 4))) AND BEYOND(c, SRID=4326;POINT (0 0), 1, kilometers)",
+	}
+	outputs := []Filter{
+		Spatial{Intersects, "geom", orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}}},
+		Distance{DWithin, "geom", orb.Point{1, 2}, 10, "meters"},
+		Distance{DWithin, "the_geom", orb.Point{1, 2}, 1.5, "statute miles"},
+		BBox{Property: "geom", Bound: orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}}},
+		BBox{Property: "geom", Bound: orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}}, CRS: "EPSG:4326"},
+		And{Spatial{Within, "a geom", orb.Point{1, 2}}, Not{Spatial{Contains, "geom", orb.Point{3, 4}}}},
+		Or{Spatial{Equals, "a", orb.Point{1, 2}}, And{Spatial{Equals, "b", orb.Point{3, 4}}, Spatial{Disjoint, "c", orb.Point{0, 0}}}},
+		And{
+			Or{Spatial{Equals, "a", orb.Point{1, 2}}, Spatial{Equals, "b", orb.Point{3, 4}}},
+			Distance{Beyond, "c", orb.Point{0, 0}, 1, "kilometers"},
+		},
+	}
+
+	for i, str := range inputs {
+		f, err := Parse(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(f, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, f)
+		}
+	}
+}
+
+func Test_parseErrors(t *testing.T) {
+	inputs := []string{
+		"",
+		"TOUCHES(geom, POINT(1 2))",
+		"INTERSECTS(geom, POINT(1 2)",
+		"INTERSECTS(geom, POINT(1))",
+		"DWITHIN(geom, POINT(1 2), 10, parsecs)",
+		"BBOX(geom, 1, 2, 3)",
+		"INTERSECTS(geom, POINT(1 2)) AND",
+		"INTERSECTS(geom, POINT(1 2)) INTERSECTS(geom, POINT(1 2))",
+		"BBOX(geom, 1, 2, 3, 4, 'EPSG:4326)",
+	}
+
+	for i, str := range inputs {
+		if _, err := Parse(str); err == nil {
+			t.Errorf("expected error on test %d", i)
+		}
+	}
+}
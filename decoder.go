@@ -0,0 +1,115 @@
+package wkttoorb
+
+import (
+	"io"
+
+	"github.com/paulmach/orb"
+)
+
+// Decoder reads successive WKT or EWKT geometries separated by white space from a stream
+type Decoder struct {
+	p *Parser
+	// types holds the geometry types to decode, all when empty
+	types map[GeometryType]bool
+}
+
+// SRID returns the SRID of the last geometry decoded or 0 if it had none
+func (d *Decoder) SRID() int {
+	return d.p.SRID()
+}
+
+// NewDecoder returns a decoder reading from r, opts configure its parser
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{p: NewParser(r, opts...)}
+}
+
+// Filter makes the decoder skip the geometries whose type is not one of types
+// the skipped geometries are not parsed, their parens are only balanced
+// so their coordinates are neither converted nor validated
+func (d *Decoder) Filter(types ...GeometryType) {
+	d.types = make(map[GeometryType]bool, len(types))
+	for _, gtype := range types {
+		d.types[gtype] = true
+	}
+}
+
+// Decode returns the next geometry of the stream
+// io.EOF is returned once the stream is exhausted
+// in recovery mode the geometry is returned along with the ErrorList
+func (d *Decoder) Decode() (orb.Geometry, error) {
+	var b OrbBuilder
+	if err := d.DecodeWith(&b); err != nil {
+		if _, ok := err.(ErrorList); ok {
+			return b.Geometry(), err
+		}
+		return nil, err
+	}
+	return b.Geometry(), nil
+}
+
+// DecodeBound returns the bound of the next geometry of the stream without building it
+// io.EOF is returned once the stream is exhausted
+func (d *Decoder) DecodeBound() (orb.Bound, error) {
+	var h boundHandler
+	if err := d.DecodeWith(&h); err != nil {
+		return orb.Bound{}, err
+	}
+	return h.bound, nil
+}
+
+// DecodeWith parses the next geometry of the stream sending the parsing events to h
+// the geometries excluded by Filter are skipped
+// io.EOF is returned once the stream is exhausted
+func (d *Decoder) DecodeWith(h Handler) error {
+	for {
+		if err := d.p.reset(h); err != nil {
+			return err
+		}
+		t, err := d.p.scanToken()
+		if err != nil {
+			return err
+		}
+		if t.ttype == Eof {
+			return io.EOF
+		}
+		d.p.unscan(t)
+		if err := d.p.parseSRID(); err != nil {
+			return d.p.recovered(err)
+		}
+		if t, err = d.p.scanToken(); err != nil {
+			return err
+		}
+		d.p.unscan(t)
+		if gtype, ok := geometryTypes[t.ttype]; !ok || len(d.types) == 0 || d.types[gtype] {
+			return d.p.recovered(d.p.parseGeometry())
+		}
+		if err := d.skip(); err != nil {
+			return err
+		}
+	}
+}
+
+// skip reads the next geometry up to its closing paren or EMPTY without parsing it
+func (d *Decoder) skip() error {
+	depth := 0
+	for {
+		t, err := d.p.scanToken()
+		if err != nil {
+			return err
+		}
+		switch t.ttype {
+		case LeftParen:
+			depth++
+		case RightParen:
+			depth--
+		case Empty:
+		case Eof:
+			return d.p.unexpected(t, "')'")
+		default:
+			continue
+		}
+		if depth <= 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package wkttoorb
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_decode(t *testing.T) {
+	input := "POINT (1 2)\nLINESTRING Z (1 2 3, 4 5 6)\n\nPOLYGON EMPTY POINT (3 4)\n"
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {4, 5}},
+		orb.Polygon{},
+		orb.Point{3, 4},
+	}
+
+	d := NewDecoder(strings.NewReader(input))
+	for i, output := range outputs {
+		geo, err := d.Decode()
+
+		if err != nil {
+			t.Errorf("unexpected error %s on geometry %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, output) {
+			t.Errorf("incorrect value returned on geometry %d", i)
+		}
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected io.EOF got %v", err)
+	}
+}
+
+func Test_decodeBound(t *testing.T) {
+	d := NewDecoder(strings.NewReader("LINESTRING (1 2, 3 4) POINT (5 6)"))
+	outputs := []orb.Bound{
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{5, 6}, Max: orb.Point{5, 6}},
+	}
+
+	for i, output := range outputs {
+		bound, err := d.DecodeBound()
+
+		if err != nil {
+			t.Errorf("unexpected error %s on geometry %d", err, i)
+		}
+		if bound != output {
+			t.Errorf("incorrect value returned on geometry %d: %v", i, bound)
+		}
+	}
+	if _, err := d.DecodeBound(); err != io.EOF {
+		t.Errorf("expected io.EOF got %v", err)
+	}
+}
+
+func Test_decodeLimits(t *testing.T) {
+	d := NewDecoder(strings.NewReader("POINT (1 2) POINT (3 4)"), WithLimits(Limits{MaxBytes: 12, MaxCoordinates: 1}))
+	for i := 0; i < 2; i++ {
+		if _, err := d.Decode(); err != nil {
+			t.Errorf("unexpected error %s on geometry %d", err, i)
+		}
+	}
+}
+
+func Test_decodeFilter(t *testing.T) {
+	input := "POINT (1 2)\nSRID=4326;POLYGON ((0 0, 1 0, 0 0))\nLINESTRING EMPTY\n" +
+		"GEOMETRYCOLLECTION (POINT EMPTY, POLYGON ((1 1, 2 1, 1 1)))\n" +
+		"MULTIPOINT ((1 1.2.3), 2)\nPOLYGON Z EMPTY\n"
+	outputs := []orb.Geometry{
+		orb.Polygon{{{0, 0}, {1, 0}, {0, 0}}},
+		orb.Polygon{},
+	}
+	srids := []int{4326, 0}
+
+	d := NewDecoder(strings.NewReader(input))
+	d.Filter(TypePolygon)
+	for i, output := range outputs {
+		geo, err := d.Decode()
+
+		if err != nil {
+			t.Errorf("unexpected error %s on geometry %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, output) {
+			t.Errorf("incorrect value returned on geometry %d: %v", i, geo)
+		}
+		if d.SRID() != srids[i] {
+			t.Errorf("incorrect srid returned on geometry %d: %d", i, d.SRID())
+		}
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("expected io.EOF got %v", err)
+	}
+}
+
+func Test_decodeFilterUnclosed(t *testing.T) {
+	d := NewDecoder(strings.NewReader("POINT (1 2"))
+	d.Filter(TypePolygon)
+	if _, err := d.Decode(); err == nil || err == io.EOF {
+		t.Errorf("expected an error got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package wkttoorb
+
+import (
+	"fmt"
+)
+
+// ordinates returns the number of values of a coordinate
+func (d Dims) ordinates() int {
+	switch d {
+	case XYZ, XYM:
+		return 3
+	case XYZM:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// inferDims returns the dimension of a coordinate without keyword
+// from its number of values, 3 values are read as Z
+func inferDims(n int) Dims {
+	switch n {
+	case 3:
+		return XYZ
+	case 4:
+		return XYZM
+	default:
+		return XY
+	}
+}
+
+// name returns the name of the dimension as used in error messages
+func (d Dims) name() string {
+	switch d {
+	case XYZ:
+		return "XYZ"
+	case XYM:
+		return "XYM"
+	case XYZM:
+		return "XYZM"
+	default:
+		return "XY"
+	}
+}
+
+// explicitDims sets the dimension given by the keyword t for the current geometry
+// the members of a geometry must all have the dimension of the first one
+func (p *Parser) explicitDims(t Token, d Dims) error {
+	if !p.dimsSet {
+		// the enclosing geometries take the dimension of their first member
+		return p.flush(d)
+	}
+	if d != p.dims {
+		return &ParseError{
+			Pos: t.pos,
+			End: t.pos + t.size(),
+			Msg: fmt.Sprintf("mixed dimensions, %s geometry in a %s geometry", d.name(), p.dims.name()),
+		}
+	}
+	return nil
+}
+
+// flush sets the dimension and sends the pending events to the handler
+func (p *Parser) flush(d Dims) error {
+	p.dims, p.dimsSet = d, true
+	for _, event := range p.pending {
+		if err := event(d); err != nil {
+			return err
+		}
+	}
+	p.pending = p.pending[:0]
+	return nil
+}
+
+// beginGeometry and the helpers below hold the events in pending until the dimension is known
+// beginGeometry also hands the dimension keyword of the source, empty if there is none,
+// to a LexemeHandler
+func (p *Parser) beginGeometry(gtype GeometryType, dimsKeyword string) error {
+	if !p.dimsSet {
+		p.pending = append(p.pending, func(d Dims) error { return p.sendBeginGeometry(gtype, d, dimsKeyword) })
+		return nil
+	}
+	return p.sendBeginGeometry(gtype, p.dims, dimsKeyword)
+}
+
+func (p *Parser) sendBeginGeometry(gtype GeometryType, dims Dims, dimsKeyword string) error {
+	if p.lh != nil && p.transform == nil {
+		return p.lh.BeginGeometryKeyword(gtype, dims, dimsKeyword)
+	}
+	return p.h.BeginGeometry(gtype, dims)
+}
+
+func (p *Parser) beginPart() error {
+	if !p.dimsSet {
+		p.pending = append(p.pending, func(Dims) error { return p.h.BeginPart() })
+		return nil
+	}
+	return p.h.BeginPart()
+}
+
+func (p *Parser) endPart() error {
+	if !p.dimsSet {
+		p.pending = append(p.pending, func(Dims) error { return p.h.EndPart() })
+		return nil
+	}
+	return p.h.EndPart()
+}
+
+// endGeometry reports an outermost geometry without any coordinate as XY
+func (p *Parser) endGeometry() error {
+	if !p.dimsSet {
+		if p.depth > 1 {
+			p.pending = append(p.pending, func(Dims) error { return p.h.EndGeometry() })
+			return nil
+		}
+		if err := p.flush(XY); err != nil {
+			return err
+		}
+	}
+	return p.h.EndGeometry()
+}
@@ -0,0 +1,104 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_inferDims(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2 3)",
+		"POINT (1 2 3 4)",
+		"LINESTRING (1 2 3, 4 5 6)",
+		"POLYGON ((1 2 3, 4 5 6, 7 8 9, 1 2 3))",
+		"MULTIPOINT (1 2 3 4, 5 6 7 8)",
+		"GEOMETRYCOLLECTION (POINT (1 2 3), LINESTRING (1 2 3, 4 5 6))",
+		"GEOMETRYCOLLECTION (POINT EMPTY, POINT Z (1 2 3), POINT (4 5 6))",
+	}
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {4, 5}},
+		orb.Polygon{{{1, 2}, {4, 5}, {7, 8}, {1, 2}}},
+		orb.MultiPoint{{1, 2}, {5, 6}},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 2}, {4, 5}}},
+		orb.Collection{orb.Point{}, orb.Point{1, 2}, orb.Point{4, 5}},
+	}
+
+	for i, str := range inputs {
+		geo, err := Scan(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+		}
+	}
+}
+
+func Test_inferDimsEvents(t *testing.T) {
+	inputs := []string{
+		"LINESTRING (1 2 3, 4 5 6)",
+		"GEOMETRYCOLLECTION (POINT (1 2 3 4))",
+		"GEOMETRYCOLLECTION (POINT Z (1 2 3))",
+	}
+	outputs := [][]string{
+		{"begin LineString 1", "1 2 3 NaN", "4 5 6 NaN", "end"},
+		{"begin GeometryCollection 3", "begin Point 3", "1 2 3 4", "end", "end"},
+		{"begin GeometryCollection 1", "begin Point 1", "1 2 3 NaN", "end", "end"},
+	}
+
+	for i, str := range inputs {
+		var h traceHandler
+		p := NewParser(strings.NewReader(str))
+		err := p.ParseWith(&h)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(h.events, outputs[i]) {
+			t.Errorf("incorrect events on test %d: %v", i, h.events)
+		}
+	}
+}
+
+func Test_mixedDims(t *testing.T) {
+	inputs := []string{
+		"POINT Z (1 2)",
+		"POINT M (1 2 3 4)",
+		"LINESTRING (1 2 3, 4 5)",
+		"LINESTRING (1 2, 4 5 6)",
+		"MULTIPOLYGON (((1 2, 3 4, 5 6, 1 2)), ((1 2 3, 4 5 6, 7 8 9, 1 2 3)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), POINT (1 2 3))",
+		"GEOMETRYCOLLECTION (POINT (1 2), POINT Z (1 2 3))",
+		"GEOMETRYCOLLECTION (POINT Z (1 2 3), POINT (1 2))",
+		"GEOMETRYCOLLECTION (POINT Z (1 2 3), POINT M (1 2 3))",
+		"GEOMETRYCOLLECTION (POINT M (1 2 3), POINT Z (1 2 3))",
+		"GEOMETRYCOLLECTION (LINESTRING Z EMPTY, POINT (1 2))",
+		"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING Z EMPTY)",
+		"GEOMETRYCOLLECTION Z (POINT M (1 2 3))",
+	}
+
+	for i, str := range inputs {
+		_, err := Scan(str)
+		if err == nil || !strings.Contains(err.Error(), "mixed dimensions") {
+			t.Errorf("expected mixed dimensions error on test %d got %v", i, err)
+		}
+	}
+}
+
+func Test_inferDimsAfterEmpty(t *testing.T) {
+	var h traceHandler
+	p := NewParser(strings.NewReader("MULTILINESTRING (EMPTY, (1 2 3, 4 5 6))"))
+	if err := p.ParseWith(&h); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	expected := []string{"begin MultiLineString 1", "(", ")", "(", "1 2 3 NaN", "4 5 6 NaN", ")", "end"}
+	if !reflect.DeepEqual(h.events, expected) {
+		t.Errorf("incorrect events: %v", h.events)
+	}
+}
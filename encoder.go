@@ -0,0 +1,146 @@
+package wkttoorb
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Encoder is a Handler writing the geometries it receives as WKT
+// the values are written in their shortest form unless the parser
+// has the KeepLexemes option, then they are written as in the source
+type Encoder struct {
+	w   io.Writer
+	err error
+
+	// stack holds the geometries and parts being written
+	stack []encoderLevel
+}
+
+// encoderLevel is the state of a geometry or a part being written
+type encoderLevel struct {
+	geometry bool
+	// n counts the elements written so far
+	n int
+}
+
+// NewEncoder returns an encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Reformat parses the WKT or EWKT string s and writes it back in a normalized form,
+// upper case keywords and single spaces, keeping the coordinate values as written
+// and the Z, M and ZM keywords only where the source has them
+func Reformat(s string, opts ...Option) (string, error) {
+	var b strings.Builder
+	p := NewParser(strings.NewReader(s), opts...)
+	p.keepLexemes = true
+	if err := p.ParseWith(NewEncoder(&b)); err != nil {
+		return "", err
+	}
+	if p.SRID() != 0 {
+		return "SRID=" + strconv.Itoa(p.SRID()) + ";" + b.String(), nil
+	}
+	return b.String(), nil
+}
+
+func (e *Encoder) write(s string) error {
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, s)
+	}
+	return e.err
+}
+
+// element writes the separator before a new element of the current level
+func (e *Encoder) element() {
+	if len(e.stack) == 0 {
+		return
+	}
+	l := &e.stack[len(e.stack)-1]
+	switch {
+	case l.n > 0:
+		e.write(", ")
+	case l.geometry:
+		e.write(" (")
+	default:
+		e.write("(")
+	}
+	l.n++
+}
+
+// end closes the current level
+func (e *Encoder) end() error {
+	l := e.stack[len(e.stack)-1]
+	e.stack = e.stack[:len(e.stack)-1]
+	switch {
+	case l.n > 0:
+		return e.write(")")
+	case l.geometry:
+		return e.write(" EMPTY")
+	default:
+		return e.write("EMPTY")
+	}
+}
+
+func (e *Encoder) BeginGeometry(gtype GeometryType, dims Dims) error {
+	switch dims {
+	case XYZ:
+		return e.beginGeometry(gtype, "Z")
+	case XYM:
+		return e.beginGeometry(gtype, "M")
+	case XYZM:
+		return e.beginGeometry(gtype, "ZM")
+	default:
+		return e.beginGeometry(gtype, "")
+	}
+}
+
+func (e *Encoder) BeginGeometryKeyword(gtype GeometryType, dims Dims, dimsKeyword string) error {
+	return e.beginGeometry(gtype, dimsKeyword)
+}
+
+func (e *Encoder) beginGeometry(gtype GeometryType, dimsKeyword string) error {
+	e.element()
+	e.write(strings.ToUpper(string(gtype)))
+	if dimsKeyword != "" {
+		e.write(" " + strings.ToUpper(dimsKeyword))
+	}
+	e.stack = append(e.stack, encoderLevel{geometry: true})
+	return e.err
+}
+
+func (e *Encoder) BeginPart() error {
+	e.element()
+	e.stack = append(e.stack, encoderLevel{})
+	return e.err
+}
+
+func (e *Encoder) Coordinate(x, y, z, m float64) error {
+	values := []string{formatFloat(x), formatFloat(y)}
+	if !math.IsNaN(z) {
+		values = append(values, formatFloat(z))
+	}
+	if !math.IsNaN(m) {
+		values = append(values, formatFloat(m))
+	}
+	return e.coordinate(values)
+}
+
+func (e *Encoder) CoordinateLexemes(x, y, z, m float64, lexemes []string) error {
+	return e.coordinate(lexemes)
+}
+
+func (e *Encoder) coordinate(values []string) error {
+	e.element()
+	return e.write(strings.Join(values, " "))
+}
+
+func (e *Encoder) EndPart() error {
+	return e.end()
+}
+
+func (e *Encoder) EndGeometry() error {
+	return e.end()
+}
@@ -0,0 +1,92 @@
+package wkttoorb
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_reformat(t *testing.T) {
+	inputs := []string{
+		"point(0.10 1e-05)",
+		"LINESTRING Z (1.000 2 -3.50, 4 5 6)",
+		"Polygon ((0 0,10 0,10 10,0 0),EMPTY)",
+		"MULTIPOINT ((1 2), EMPTY, 3.0 4.0)",
+		"MULTIPOLYGON (((0 0, 1 0, 0 0)), EMPTY)",
+		"GEOMETRYCOLLECTION (POINT M (1 2 3), LINESTRING EMPTY)",
+		"SRID=4326;POINT EMPTY",
+		"GEOMETRYCOLLECTION (POINT EMPTY, POINT (1 2 3))",
+		"multipoint z ((1 2 3), (4 5 6))",
+	}
+	outputs := []string{
+		"POINT (0.10 1e-05)",
+		"LINESTRING Z (1.000 2 -3.50, 4 5 6)",
+		"POLYGON ((0 0, 10 0, 10 10, 0 0), EMPTY)",
+		"MULTIPOINT (1 2, EMPTY, 3.0 4.0)",
+		"MULTIPOLYGON (((0 0, 1 0, 0 0)), EMPTY)",
+		"GEOMETRYCOLLECTION (POINT M (1 2 3), LINESTRING EMPTY)",
+		"SRID=4326;POINT EMPTY",
+		"GEOMETRYCOLLECTION (POINT EMPTY, POINT (1 2 3))",
+		"MULTIPOINT Z (1 2 3, 4 5 6)",
+	}
+
+	for i, str := range inputs {
+		out, err := Reformat(str)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if out != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %s", i, out)
+		}
+	}
+}
+
+func Test_encoder(t *testing.T) {
+	var b strings.Builder
+	p := NewParser(strings.NewReader("POINT ZM (0.10 1e-05 3.000 -0)"))
+	if err := p.ParseWith(NewEncoder(&b)); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if out := b.String(); out != "POINT ZM (0.1 0.00001 3 -0)" {
+		t.Errorf("incorrect value returned: %s", out)
+	}
+
+	// the lexemes do not match transformed values
+	out, err := Reformat("POINT (1.50 2.0)", WithTransform(SwapXY))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if out != "POINT (2 1.5)" {
+		t.Errorf("incorrect value returned: %s", out)
+	}
+}
+
+func Test_reformatOptions(t *testing.T) {
+	inputs := []string{
+		"POINT (1.50 2)",
+		"LINESTRING Z (1.0 2 3, 4 5 6)",
+		"GEOMETRYCOLLECTION (POINT (1 2.0), LINESTRING EMPTY)",
+		"POINT (1.50 2)",
+	}
+	opts := []Option{
+		PromoteToMulti(),
+		PromoteToMulti(),
+		PromoteToMulti(),
+		WithGridSize(1),
+	}
+	outputs := []string{
+		"MULTIPOINT (1.50 2)",
+		"MULTILINESTRING Z ((1.0 2 3, 4 5 6))",
+		"GEOMETRYCOLLECTION (MULTIPOINT (1 2.0), MULTILINESTRING EMPTY)",
+		"POINT (2 2)",
+	}
+
+	for i, str := range inputs {
+		out, err := Reformat(str, opts[i])
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if out != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %s", i, out)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetWidth is the maximum number of bytes of the source shown by FormatError
+// around the error position
+const snippetWidth = 60
+
+// ParseError is a syntax error in a WKT string
+type ParseError struct {
+	// Pos and End are the byte offsets of the start and the end of the offending token
+	Pos, End int
+	Msg      string
+	// Expected lists the tokens valid at Pos, it may be empty
+	Expected []string
+}
+
+func (e *ParseError) Error() string {
+	s := fmt.Sprintf("%s on pos %d", e.Msg, e.Pos)
+	if len(e.Expected) > 0 {
+		s += " expected " + strings.Join(e.Expected, " or ")
+	}
+	return s
+}
+
+// ErrorList is the list of syntax errors found by a parser in recovery mode
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+	}
+}
+
+// FormatError renders err for the WKT string src, each ParseError as the excerpt
+// of src around it with a caret under the offending token and the expected tokens
+// long inputs are truncated around the error, other errors are rendered with Error
+func FormatError(src string, err error) string {
+	switch err := err.(type) {
+	case *ParseError:
+		return err.format(src)
+	case ErrorList:
+		parts := make([]string, len(err))
+		for i, e := range err {
+			parts[i] = e.format(src)
+		}
+		return strings.Join(parts, "\n\n")
+	default:
+		return err.Error()
+	}
+}
+
+func (e *ParseError) format(src string) string {
+	pos := e.Pos
+	if pos > len(src) {
+		pos = len(src)
+	}
+
+	// the excerpt is the line of the error, truncated to snippetWidth bytes around it
+	// if it is longer
+	start := strings.LastIndexByte(src[:pos], '\n') + 1
+	end := len(src)
+	if i := strings.IndexByte(src[pos:], '\n'); i >= 0 {
+		end = pos + i
+	}
+	prefix, suffix := "", ""
+	if end-start > snippetWidth {
+		if pos-start > snippetWidth/2 {
+			// the excerpt is centered on the error unless it is near the end of the line
+			from := pos - snippetWidth/2
+			if end-from < snippetWidth {
+				from = end - snippetWidth
+			}
+			start, prefix = runeStart(src, from), "..."
+		}
+		if end-start > snippetWidth {
+			end, suffix = runeStart(src, start+snippetWidth), "..."
+		}
+	}
+
+	line := strings.Replace(src[start:end], "\t", " ", -1)
+	col := utf8.RuneCountInString(prefix) + utf8.RuneCountInString(src[start:pos])
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s on pos %d\n", e.Msg, e.Pos)
+	fmt.Fprintf(&b, "  %s%s%s\n", prefix, line, suffix)
+	fmt.Fprintf(&b, "  %s^", strings.Repeat(" ", col))
+	switch len(e.Expected) {
+	case 0:
+	case 1:
+		fmt.Fprintf(&b, "\nexpected %s", e.Expected[0])
+	default:
+		fmt.Fprintf(&b, "\nexpected one of: %s", strings.Join(e.Expected, ", "))
+	}
+	return b.String()
+}
+
+// runeStart returns the offset of the start of the rune of s at i
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// WithRecovery makes the parser carry on after a syntax error
+// the invalid coordinate, part or member is skipped up to the next ',' or ')'
+// and parsing resumes from there, all the errors are returned as an ErrorList
+// and Parse returns the geometry made of the valid elements along with it
+func WithRecovery() Option {
+	return func(p *Parser) {
+		p.recovering = true
+	}
+}
+
+// unexpected returns the error for the unexpected token t and puts it back
+// so that recovery can resynchronise on it
+func (p *Parser) unexpected(t Token, expected ...string) *ParseError {
+	p.unscan(t)
+	msg := "unexpected token " + t.text()
+	if t.ttype == Eof {
+		msg = "unexpected end of input"
+	}
+	return &ParseError{Pos: t.pos, End: t.pos + t.size(), Msg: msg, Expected: expected}
+}
+
+// addError records e unless an error was already recorded at the same position
+func (p *Parser) addError(e *ParseError) {
+	if n := len(p.errs); n > 0 && p.errs[n-1].Pos == e.Pos {
+		return
+	}
+	p.errs = append(p.errs, e)
+}
+
+// resync records err and skips the tokens up to the next ',' or ')' of the current list,
+// leaving it to be scanned next
+// it returns err if it cannot be recovered from, when not in recovery mode,
+// for limits and cancellation or for handler errors
+func (p *Parser) resync(err error) error {
+	e, ok := err.(*ParseError)
+	if !ok || !p.recovering {
+		return err
+	}
+	p.addError(e)
+
+	depth := 0
+	for {
+		t, err := p.scanToken()
+		if _, ok := err.(*ParseError); ok {
+			// the invalid token is skipped with the rest of the element
+			continue
+		} else if err != nil {
+			return err
+		}
+		switch t.ttype {
+		case LeftParen:
+			depth++
+		case RightParen:
+			if depth == 0 {
+				p.unscan(t)
+				return nil
+			}
+			depth--
+		case Comma:
+			if depth == 0 {
+				p.unscan(t)
+				return nil
+			}
+		case Eof:
+			p.addError(p.unexpected(t, "')'"))
+			return nil
+		}
+	}
+}
+
+// recovered returns the errors of the geometry just parsed
+// in recovery mode the syntax errors are returned as an ErrorList
+func (p *Parser) recovered(err error) error {
+	if e, ok := err.(*ParseError); ok && p.recovering {
+		p.addError(e)
+	} else if err != nil {
+		return err
+	}
+	if len(p.errs) > 0 {
+		return p.errs
+	}
+	return nil
+}
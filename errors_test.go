@@ -0,0 +1,149 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_parseError(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2",
+		"LINESTRING (1 2, 3)",
+		"LINESTRING (1 2, 3 4 x)",
+		"POLYGON ((0 0, 1 1, 0 0) (1 1, 2 2, 1 1))",
+		"POINT (1 2) 3",
+		"SRID=4326 POINT (1 2)",
+	}
+	outputs := []*ParseError{
+		{Pos: 10, End: 10, Msg: "unexpected end of input", Expected: []string{"')'"}},
+		{Pos: 18, End: 19, Msg: "unexpected token )", Expected: []string{"number"}},
+		{Pos: 21, End: 22, Msg: "unexpected word x"},
+		{Pos: 25, End: 26, Msg: "unexpected token (", Expected: []string{"','", "')'"}},
+		{Pos: 12, End: 13, Msg: "unexpected token 3", Expected: []string{"end of input"}},
+		{Pos: 10, End: 15, Msg: "unexpected token point", Expected: []string{"';'"}},
+	}
+
+	for i, str := range inputs {
+		_, err := Scan(str)
+		if !reflect.DeepEqual(err, outputs[i]) {
+			t.Errorf("incorrect error returned on test %d: %#v", i, err)
+		}
+	}
+}
+
+func Test_recovery(t *testing.T) {
+	inputs := []string{
+		"LINESTRING (1 2, 3 x 4, 5 6, 7 8 9, 10 11)",
+		"LINESTRING (1 2, 3 4 5 6 7, 8 9)",
+		"POLYGON ((0 0, 1 0, 1.2.3 1, 0 0), (a), (0 0, 1 1, 0 0))",
+		"MULTIPOINT ((1 2), (3 4 5 6 7), 8 9",
+		"GEOMETRYCOLLECTION (POINT (1 2), POINT 3 4, LINESTRING (5 6, 7 8))",
+		"MULTIPOLYGON (((0 0, 1 0, 0 0)), ((2 2, 3 2, 2 2)",
+	}
+	geometries := []orb.Geometry{
+		orb.LineString{{1, 2}, {5, 6}, {10, 11}},
+		orb.LineString{{1, 2}, {8, 9}},
+		orb.Polygon{{{0, 0}, {1, 0}, {0, 0}}, {}, {{0, 0}, {1, 1}, {0, 0}}},
+		orb.MultiPoint{{1, 2}, {8, 9}},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{5, 6}, {7, 8}}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {0, 0}}}, {{{2, 2}, {3, 2}, {2, 2}}}},
+	}
+	positions := [][]int{
+		{19, 29},
+		{17},
+		{20, 36},
+		{20, 35},
+		{39},
+		{49},
+	}
+
+	for i, str := range inputs {
+		g, err := Scan(str, WithRecovery())
+		errs, ok := err.(ErrorList)
+		if !ok {
+			t.Errorf("expected an error list on test %d, got %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(g, geometries[i]) {
+			t.Errorf("incorrect geometry returned on test %d: %v", i, g)
+		}
+		var pos []int
+		for _, e := range errs {
+			pos = append(pos, e.Pos)
+		}
+		if !reflect.DeepEqual(pos, positions[i]) {
+			t.Errorf("incorrect errors returned on test %d: %v", i, errs)
+		}
+	}
+}
+
+func Test_recoveryValid(t *testing.T) {
+	g, err := Scan("POLYGON ((0 0, 1 0, 0 0))", WithRecovery())
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(g, orb.Polygon{{{0, 0}, {1, 0}, {0, 0}}}) {
+		t.Errorf("incorrect geometry returned %v", g)
+	}
+}
+
+func Test_formatError(t *testing.T) {
+	inputs := []string{
+		"POLYGON ((0 0, 1 1, 0 0) (1 1, 2 2, 1 1))",
+		"POINT (1 2",
+		"MULTIPOINT (0 0, 1 1, 2 2, 3 3, 4 4, 5 5, 6 6, 7 7, 8 8, 9 9, 10 10 x, 11 11, 12 12, 13 13, 14 14, 15 15)",
+		"LINESTRING (\n\t1 2,\n\t3)",
+		"POLYGON ((10 20, 30 40, 50 60, 10 20) x, 1 2)",
+		"MULTIPOINT (0 0, 1 1, 2 2, 3 3, 4 4, 5 5, 6 6, 7 7, 8 8, 9 9, 10 10, 11 11, 12 12 x)",
+	}
+	outputs := []string{
+		"unexpected token ( on pos 25\n" +
+			"  POLYGON ((0 0, 1 1, 0 0) (1 1, 2 2, 1 1))\n" +
+			"                           ^\n" +
+			"expected one of: ',', ')'",
+		"unexpected end of input on pos 10\n" +
+			"  POINT (1 2\n" +
+			"            ^\n" +
+			"expected ')'",
+		"unexpected word x on pos 68\n" +
+			"  ... 5, 6 6, 7 7, 8 8, 9 9, 10 10 x, 11 11, 12 12, 13 13, 14 14,...\n" +
+			"                                   ^",
+		"unexpected token ) on pos 21\n" +
+			"   3)\n" +
+			"    ^\n" +
+			"expected number",
+		"unexpected word x on pos 38\n" +
+			"  POLYGON ((10 20, 30 40, 50 60, 10 20) x, 1 2)\n" +
+			"                                        ^",
+		"unexpected word x on pos 82\n" +
+			"  ...2, 3 3, 4 4, 5 5, 6 6, 7 7, 8 8, 9 9, 10 10, 11 11, 12 12 x)\n" +
+			"                                                               ^",
+	}
+
+	for i, str := range inputs {
+		_, err := Scan(str)
+		if err == nil {
+			t.Errorf("expected an error on test %d", i)
+			continue
+		}
+		if out := FormatError(str, err); out != outputs[i] {
+			t.Errorf("incorrect output on test %d:\n%s", i, out)
+		}
+	}
+}
+
+func Test_formatErrorList(t *testing.T) {
+	str := "LINESTRING (1 2, x, 3 4 5)"
+	_, err := Scan(str, WithRecovery())
+	expected := "unexpected word x on pos 17\n" +
+		"  LINESTRING (1 2, x, 3 4 5)\n" +
+		"                   ^\n\n" +
+		"mixed dimensions, coordinate has 3 values expected 2 on pos 20\n" +
+		"  LINESTRING (1 2, x, 3 4 5)\n" +
+		"                      ^"
+	if out := FormatError(str, err); out != expected {
+		t.Errorf("incorrect output:\n%s", out)
+	}
+}
@@ -0,0 +1,155 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ScanExtent parses a bounding box literal into an orb bound
+// the supported forms are
+//
+//	BOX(minX minY, maxX maxY) as written by PostGIS
+//	BOX3D(minX minY minZ, maxX maxY maxZ), the Z values are dropped
+//	ENVELOPE(minX, maxX, maxY, minY) as used by Solr and ECQL
+//	BBOX(minX, minY, maxX, maxY)
+func ScanExtent(s string) (orb.Bound, error) {
+	p := NewParser(strings.NewReader(s))
+	return p.ParseExtent()
+}
+
+// ParseExtent parses a bounding box literal, see ScanExtent for the supported forms
+func (p *Parser) ParseExtent() (orb.Bound, error) {
+	b, err := p.parseExtent()
+	if err != nil {
+		return b, err
+	}
+
+	return b, p.expectEof()
+}
+
+func (p *Parser) parseExtent() (orb.Bound, error) {
+	t, err := p.scanToken()
+	if err != nil {
+		return orb.Bound{}, err
+	}
+
+	var groups []int
+	switch t.ttype {
+	case Box:
+		groups = []int{2, 2}
+	case Box3D:
+		groups = []int{3, 3}
+	case Envelope, BBox:
+		groups = []int{1, 1, 1, 1}
+	default:
+		return orb.Bound{}, p.unexpected(t, "box", "box3d", "envelope", "bbox")
+	}
+	v, err := p.parseExtentText(groups)
+	if err != nil {
+		return orb.Bound{}, err
+	}
+
+	var corners orb.MultiPoint
+	switch t.ttype {
+	case Box:
+		corners = orb.MultiPoint{{v[0], v[1]}, {v[2], v[3]}}
+	case Box3D:
+		corners = orb.MultiPoint{{v[0], v[1]}, {v[3], v[4]}}
+	case Envelope:
+		corners = orb.MultiPoint{{v[0], v[3]}, {v[1], v[2]}}
+	case BBox:
+		corners = orb.MultiPoint{{v[0], v[1]}, {v[2], v[3]}}
+	}
+	return corners.Bound(), nil
+}
+
+// parseExtentText parses the values of an extent between parens
+// groups gives the number of values of each comma separated group
+func (p *Parser) parseExtentText(groups []int) ([]float64, error) {
+	t, err := p.scanToken()
+	if err != nil {
+		return nil, err
+	}
+	if t.ttype != LeftParen {
+		return nil, p.unexpected(t, "'('")
+	}
+
+	var values []float64
+	for i, n := range groups {
+		if i > 0 {
+			t, err = p.scanToken()
+			if err != nil {
+				return nil, err
+			}
+			if t.ttype != Comma {
+				return nil, p.unexpected(t, "','")
+			}
+		}
+		for j := 0; j < n; j++ {
+			v, err := p.parseNumber()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+	}
+
+	t, err = p.scanToken()
+	if err != nil {
+		return nil, err
+	}
+	if t.ttype != RightParen {
+		return nil, p.unexpected(t, "')'")
+	}
+	return values, nil
+}
+
+// parseNumber parses a single Float token
+func (p *Parser) parseNumber() (float64, error) {
+	t, err := p.scanToken()
+	if err != nil {
+		return 0, err
+	}
+	if t.ttype != Float {
+		return 0, p.unexpected(t, "number")
+	}
+	v, err := strconv.ParseFloat(string(t.num), 64)
+	if err != nil {
+		return 0, &ParseError{Pos: t.pos, End: p.end, Msg: "invalid number " + t.text()}
+	}
+	return v, nil
+}
+
+// FormatBox returns b in the PostGIS form BOX(minX minY,maxX maxY)
+func FormatBox(b orb.Bound) string {
+	return fmt.Sprintf("BOX(%s %s,%s %s)",
+		formatFloat(b.Min[0]), formatFloat(b.Min[1]), formatFloat(b.Max[0]), formatFloat(b.Max[1]))
+}
+
+// FormatBox3D returns b with the Z range minZ, maxZ in the PostGIS form
+// BOX3D(minX minY minZ,maxX maxY maxZ), the Z range is given separately
+// as orb.Bound has no Z, pass 0 for both to write a 2D bound as PostGIS does
+func FormatBox3D(b orb.Bound, minZ, maxZ float64) string {
+	return fmt.Sprintf("BOX3D(%s %s %s,%s %s %s)",
+		formatFloat(b.Min[0]), formatFloat(b.Min[1]), formatFloat(minZ),
+		formatFloat(b.Max[0]), formatFloat(b.Max[1]), formatFloat(maxZ))
+}
+
+// FormatEnvelope returns b in the form ENVELOPE(minX, maxX, maxY, minY)
+func FormatEnvelope(b orb.Bound) string {
+	return fmt.Sprintf("ENVELOPE(%s, %s, %s, %s)",
+		formatFloat(b.Min[0]), formatFloat(b.Max[0]), formatFloat(b.Max[1]), formatFloat(b.Min[1]))
+}
+
+// FormatBBox returns b in the form BBOX(minX, minY, maxX, maxY)
+func FormatBBox(b orb.Bound) string {
+	return fmt.Sprintf("BBOX(%s, %s, %s, %s)",
+		formatFloat(b.Min[0]), formatFloat(b.Min[1]), formatFloat(b.Max[0]), formatFloat(b.Max[1]))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
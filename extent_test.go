@@ -0,0 +1,66 @@
+package wkttoorb
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_scanExtent(t *testing.T) {
+	inputs := []string{
+		"BOX(1 2,3 4)",
+		"box3d(1 2 5, 3 4 6)",
+		"ENVELOPE(1, 3, 4, 2)",
+		"BBOX(1, 2, 3, 4)",
+		"BOX(3 4, 1 2)",
+		"BOX(-1.5 2e1,3 4)",
+	}
+	outputs := []orb.Bound{
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{1, 2}, Max: orb.Point{3, 4}},
+		{Min: orb.Point{-1.5, 4}, Max: orb.Point{3, 20}},
+	}
+
+	for i, str := range inputs {
+		b, err := ScanExtent(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if b != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %v", i, b)
+		}
+	}
+
+	for i, str := range []string{"BOX(1 2 3 4)", "ENVELOPE(1, 2, 3)", "POINT (1 2)", "BBOX(1, 2, 3, 4) x"} {
+		if _, err := ScanExtent(str); err == nil {
+			t.Errorf("expected error on invalid extent %d", i)
+		}
+	}
+}
+
+func Test_formatExtent(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{1.5, -2}, Max: orb.Point{3, 4}}
+	formats := map[string]string{
+		FormatBox(b):            "BOX(1.5 -2,3 4)",
+		FormatEnvelope(b):       "ENVELOPE(1.5, 3, 4, -2)",
+		FormatBBox(b):           "BBOX(1.5, -2, 3, 4)",
+		FormatBox3D(b, -1, 2.5): "BOX3D(1.5 -2 -1,3 4 2.5)",
+	}
+
+	for out, expected := range formats {
+		if out != expected {
+			t.Errorf("incorrect format %s expected %s", out, expected)
+		}
+		parsed, err := ScanExtent(out)
+		if err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+		if parsed != b {
+			t.Errorf("incorrect round trip for %s: %v", out, parsed)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package gogeom parses WKT into github.com/twpayne/go-geom geometries
+// using the wkttoorb parser, keeping the Z and M values
+package gogeom
+
+import (
+	"strings"
+
+	"github.com/Succo/wkttoorb"
+	"github.com/twpayne/go-geom"
+)
+
+// Scan parses the WKT or EWKT string s into a go-geom geometry
+func Scan(s string, opts ...wkttoorb.Option) (geom.T, error) {
+	var b Builder
+	p := wkttoorb.NewParser(strings.NewReader(s), opts...)
+	if err := p.ParseWith(&b); err != nil {
+		return nil, err
+	}
+	return setSRID(b.Geometry(), p.SRID()), nil
+}
+
+// setSRID sets the srid of g, go-geom only exposes it on the concrete types
+func setSRID(g geom.T, srid int) geom.T {
+	switch g := g.(type) {
+	case *geom.Point:
+		return g.SetSRID(srid)
+	case *geom.LineString:
+		return g.SetSRID(srid)
+	case *geom.Polygon:
+		return g.SetSRID(srid)
+	case *geom.MultiPoint:
+		return g.SetSRID(srid)
+	case *geom.MultiLineString:
+		return g.SetSRID(srid)
+	case *geom.MultiPolygon:
+		return g.SetSRID(srid)
+	case *geom.GeometryCollection:
+		return g.SetSRID(srid)
+	default:
+		return g
+	}
+}
+
+var layouts = map[wkttoorb.Dims]geom.Layout{
+	wkttoorb.XY:   geom.XY,
+	wkttoorb.XYZ:  geom.XYZ,
+	wkttoorb.XYM:  geom.XYM,
+	wkttoorb.XYZM: geom.XYZM,
+}
+
+// Builder is a wkttoorb.Handler building go-geom geometries
+type Builder struct {
+	// stack holds the geometries being built, more than one for collections
+	stack []frame
+
+	geom geom.T
+}
+
+// frame is the state of a geometry being built
+type frame struct {
+	gtype  wkttoorb.GeometryType
+	layout geom.Layout
+	depth  int
+
+	flat  []float64
+	ends  []int
+	endss [][]int
+	coll  *geom.GeometryCollection
+}
+
+// Geometry returns the last geometry built
+func (b *Builder) Geometry() geom.T {
+	return b.geom
+}
+
+func (b *Builder) top() *frame {
+	return &b.stack[len(b.stack)-1]
+}
+
+func (b *Builder) BeginGeometry(gtype wkttoorb.GeometryType, dims wkttoorb.Dims) error {
+	f := frame{gtype: gtype, layout: layouts[dims]}
+	if gtype == wkttoorb.TypeGeometryCollection {
+		f.coll = geom.NewGeometryCollection()
+		// the layout is kept for EMPTY collections and checked against the members
+		if err := f.coll.SetLayout(f.layout); err != nil {
+			return err
+		}
+	}
+	b.stack = append(b.stack, f)
+	return nil
+}
+
+func (b *Builder) BeginPart() error {
+	b.top().depth++
+	return nil
+}
+
+func (b *Builder) Coordinate(x, y, z, m float64) error {
+	f := b.top()
+	f.flat = append(f.flat, x, y)
+	if f.layout.ZIndex() != -1 {
+		f.flat = append(f.flat, z)
+	}
+	if f.layout.MIndex() != -1 {
+		f.flat = append(f.flat, m)
+	}
+	if f.gtype == wkttoorb.TypeMultiPoint {
+		f.ends = append(f.ends, len(f.flat))
+	}
+	return nil
+}
+
+func (b *Builder) EndPart() error {
+	f := b.top()
+	if f.gtype == wkttoorb.TypeMultiPolygon && f.depth == 1 {
+		f.endss = append(f.endss, f.ends)
+		f.ends = nil
+	} else {
+		f.ends = append(f.ends, len(f.flat))
+	}
+	f.depth--
+	return nil
+}
+
+func (b *Builder) EndGeometry() error {
+	g := b.top().geometry()
+	b.stack = b.stack[:len(b.stack)-1]
+	if len(b.stack) > 0 {
+		return b.top().coll.Push(g)
+	}
+	b.geom = g
+	return nil
+}
+
+func (f *frame) geometry() geom.T {
+	switch f.gtype {
+	case wkttoorb.TypePoint:
+		if f.flat == nil {
+			return geom.NewPointEmpty(f.layout)
+		}
+		return geom.NewPointFlat(f.layout, f.flat)
+	case wkttoorb.TypeLineString:
+		return geom.NewLineStringFlat(f.layout, f.flat)
+	case wkttoorb.TypeMultiPoint:
+		return geom.NewMultiPointFlat(f.layout, f.flat, geom.NewMultiPointFlatOptionWithEnds(f.ends))
+	case wkttoorb.TypePolygon:
+		return geom.NewPolygonFlat(f.layout, f.flat, f.ends)
+	case wkttoorb.TypeMultiLineString:
+		return geom.NewMultiLineStringFlat(f.layout, f.flat, f.ends)
+	case wkttoorb.TypeMultiPolygon:
+		return geom.NewMultiPolygonFlat(f.layout, f.flat, f.endss)
+	default:
+		return f.coll
+	}
+}
@@ -0,0 +1,96 @@
+package gogeom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twpayne/go-geom"
+)
+
+func Test_scan(t *testing.T) {
+	inputs := []string{
+		"POINT EMPTY",
+		"POINT (1 2)",
+		"POINT ZM (1 2 3 4)",
+		"LINESTRING M (1 2 3, 4 5 6)",
+		"LINESTRING (1 2 3, 4 5 6)",
+		"POLYGON Z ((1 2 3, 4 5 6, 7 8 9, 1 2 3))",
+		"MULTIPOINT (1 2, 3 4)",
+		"MULTILINESTRING ((1 2, 3 4), (5 6, 7 8))",
+		"MULTIPOLYGON (((1 2, 3 4, 5 6, 1 2)), ((1 2, 3 4, 5 6, 1 2), (1 2, 3 4, 5 6, 1 2)))",
+	}
+	outputs := []geom.T{
+		geom.NewPointEmpty(geom.XY),
+		geom.NewPointFlat(geom.XY, []float64{1, 2}),
+		geom.NewPointFlat(geom.XYZM, []float64{1, 2, 3, 4}),
+		geom.NewLineStringFlat(geom.XYM, []float64{1, 2, 3, 4, 5, 6}),
+		geom.NewLineStringFlat(geom.XYZ, []float64{1, 2, 3, 4, 5, 6}),
+		geom.NewPolygonFlat(geom.XYZ, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 1, 2, 3}, []int{12}),
+		geom.NewMultiPointFlat(geom.XY, []float64{1, 2, 3, 4}),
+		geom.NewMultiLineStringFlat(geom.XY, []float64{1, 2, 3, 4, 5, 6, 7, 8}, []int{4, 8}),
+		geom.NewMultiPolygonFlat(geom.XY, []float64{
+			1, 2, 3, 4, 5, 6, 1, 2,
+			1, 2, 3, 4, 5, 6, 1, 2,
+			1, 2, 3, 4, 5, 6, 1, 2,
+		}, [][]int{{8}, {16, 24}}),
+	}
+
+	for i, str := range inputs {
+		g, err := Scan(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_scanCollection(t *testing.T) {
+	g, err := Scan("GEOMETRYCOLLECTION Z (POINT (1 2 3), LINESTRING Z (1 2 3, 4 5 6))")
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	expected := geom.NewGeometryCollection()
+	expected.MustSetLayout(geom.XYZ)
+	expected.MustPush(
+		geom.NewPointFlat(geom.XYZ, []float64{1, 2, 3}),
+		geom.NewLineStringFlat(geom.XYZ, []float64{1, 2, 3, 4, 5, 6}),
+	)
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect value returned: %#v", g)
+	}
+}
+
+func Test_scanCollectionEmpty(t *testing.T) {
+	inputs := []string{
+		"GEOMETRYCOLLECTION EMPTY",
+		"GEOMETRYCOLLECTION Z EMPTY",
+		"GEOMETRYCOLLECTION M EMPTY",
+		"GEOMETRYCOLLECTION ZM EMPTY",
+	}
+	outputs := []geom.Layout{geom.XY, geom.XYZ, geom.XYM, geom.XYZM}
+
+	for i, str := range inputs {
+		g, err := Scan(str)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+			continue
+		}
+		if g.Layout() != outputs[i] || !g.Empty() {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_scanSRID(t *testing.T) {
+	g, err := Scan("SRID=4326;POINT (1 2)")
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if g.SRID() != 4326 {
+		t.Errorf("incorrect srid %d", g.SRID())
+	}
+}
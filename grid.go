@@ -0,0 +1,48 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// WithGridSize snaps the X and Y values of every parsed coordinate to a grid of the given size
+// consecutive duplicate points are then removed from lines and rings, and the lines
+// and rings collapsing below 2 and 4 points are dropped along with the holes of
+// a polygon whose exterior ring collapsed
+// the size must be positive, parsing fails otherwise
+func WithGridSize(size float64) Option {
+	snap := grid(size)
+	return func(p *Parser) {
+		if !(size > 0) {
+			p.optErr = fmt.Errorf("invalid grid size %v", size)
+			return
+		}
+		WithTransform(func(point orb.Point) orb.Point {
+			return orb.Point{snap(point[0]), snap(point[1])}
+		})(p)
+		p.filters = append(p.filters, dedupe)
+	}
+}
+
+// grid returns the function snapping a value to a grid of the given size
+// sizes such as 1e-7 are applied through their inverse which keeps the
+// snapped values as close as possible to their decimal form
+func grid(size float64) func(float64) float64 {
+	if inv := math.Round(1 / size); size < 1 && math.Abs(inv*size-1) < 1e-9 {
+		return func(v float64) float64 { return math.Round(v*inv) / inv }
+	}
+	return func(v float64) float64 { return math.Round(v/size) * size }
+}
+
+// dedupe removes the consecutive duplicate points of coords
+func dedupe(coords []coord, ring bool) []coord {
+	out := coords[:1]
+	for _, c := range coords[1:] {
+		if c.point != out[len(out)-1].point {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package wkttoorb
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_gridSize(t *testing.T) {
+	inputs := []string{
+		"POINT (1.23456789 -0.00000004)",
+		"LINESTRING (0 0, 0.4 0.4, 1 1, 1.2 0.9, 2 2)",
+		"LINESTRING (0 0, 0.1 0.1, 0.2 -0.2)",
+		"POLYGON ((0 0, 10 0, 10 10, 0 0), (1 1, 1.2 1, 1.2 1.2, 1 1), EMPTY)",
+		"POLYGON ((0 0, 0.2 0, 0.2 0.2, 0 0), (1 1, 5 1, 5 5, 1 1))",
+		"MULTIPOLYGON (((0 0, 0.2 0, 0.2 0.2, 0 0)), ((0 0, 10 0, 10 10, 0 0)))",
+		"MULTILINESTRING ((0 0, 0.1 0), EMPTY, (0 0, 3 3))",
+		"MULTIPOINT (0.1 0.1, 0.2 0.2)",
+		"GEOMETRYCOLLECTION (LINESTRING (0 0, 0.1 0.1), POINT (2.6 3.4))",
+	}
+	sizes := []float64{1e-7, 1, 1, 1, 1, 1, 1, 1, 1}
+	outputs := []orb.Geometry{
+		orb.Point{1.2345679, 0},
+		orb.LineString{{0, 0}, {1, 1}, {2, 2}},
+		orb.LineString{},
+		orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}, {}},
+		orb.Polygon{},
+		orb.MultiPolygon{{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}}},
+		orb.MultiLineString{{}, {{0, 0}, {3, 3}}},
+		orb.MultiPoint{{0, 0}, {0, 0}},
+		orb.Collection{orb.LineString{}, orb.Point{3, 3}},
+	}
+
+	for i, str := range inputs {
+		g, err := Scan(str, WithGridSize(sizes[i]))
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_gridSizeZ(t *testing.T) {
+	trace := &traceHandler{}
+	p := NewParser(strings.NewReader("LINESTRING Z (0 0 1, 0.1 0 2, 1 1 3)"), WithGridSize(1))
+	if err := p.ParseWith(trace); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := []string{"begin LineString 1", "0 0 1 NaN", "1 1 3 NaN", "end"}
+	if !reflect.DeepEqual(trace.events, expected) {
+		t.Errorf("incorrect events %v", trace.events)
+	}
+}
+
+func Test_gridSizeInvalid(t *testing.T) {
+	inputs := []float64{0, -1, math.NaN()}
+
+	for i, size := range inputs {
+		if _, err := Scan("POINT (1.26 2.5)", WithGridSize(size)); err == nil {
+			t.Errorf("expected an error on test %d", i)
+		}
+		d := NewDecoder(strings.NewReader("POINT (1 2)"), WithGridSize(size))
+		if _, err := d.Decode(); err == nil {
+			t.Errorf("expected an error decoding test %d", i)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package wkttoorb
+
+// GeometryType is the type of a parsed geometry
+// its values match the GeoJSONType of the orb geometries
+type GeometryType string
+
+const (
+	TypePoint           GeometryType = "Point"
+	TypeLineString      GeometryType = "LineString"
+	TypePolygon         GeometryType = "Polygon"
+	TypeMultiPoint      GeometryType = "MultiPoint"
+	TypeMultiLineString GeometryType = "MultiLineString"
+	TypeMultiPolygon    GeometryType = "MultiPolygon"
+
+	TypeGeometryCollection GeometryType = "GeometryCollection"
+)
+
+// geometryTypes maps the geometry keywords to their type
+var geometryTypes = map[tokenType]GeometryType{
+	Point:           TypePoint,
+	Linestring:      TypeLineString,
+	Polygon:         TypePolygon,
+	Multipoint:      TypeMultiPoint,
+	MultilineString: TypeMultiLineString,
+	MultiPolygon:    TypeMultiPolygon,
+
+	GeometryCollection: TypeGeometryCollection,
+}
+
+// Dims is the dimension of the coordinates of a geometry
+type Dims int
+
+const (
+	XY Dims = iota
+	XYZ
+	XYM
+	XYZM
+)
+
+// dimensions maps the dimension keywords to their value
+var dimensions = map[tokenType]Dims{
+	Z:  XYZ,
+	M:  XYM,
+	ZM: XYZM,
+}
+
+// HasZ returns true if the coordinates have a Z value
+func (d Dims) HasZ() bool {
+	return d == XYZ || d == XYZM
+}
+
+// HasM returns true if the coordinates have a M value
+func (d Dims) HasM() bool {
+	return d == XYM || d == XYZM
+}
+
+// Handler receives the events produced while parsing a geometry
+// returning an error from any method stops the parsing
+//
+// Every geometry is reported between BeginGeometry and EndGeometry,
+// an EMPTY geometry has no other event in between.
+// Points, linestrings and multipoints report their coordinates directly.
+// Each ring of a polygon and each line of a multilinestring is a part.
+// Each polygon of a multipolygon is a part, containing a part for each ring.
+// A geometry collection reports each of its members as a nested geometry.
+// EMPTY rings and members of multi geometries, including the points of a
+// multipoint, are reported as empty parts unless the DropEmpty option is used.
+type Handler interface {
+	BeginGeometry(gtype GeometryType, dims Dims) error
+	BeginPart() error
+	// Coordinate receives a coordinate, z and m are NaN if the geometry does not have them
+	Coordinate(x, y, z, m float64) error
+	EndPart() error
+	EndGeometry() error
+}
+
+// LexemeHandler is a Handler receiving the source text of the coordinate values
+// and of the dimension keywords, with the KeepLexemes option the parser calls
+// BeginGeometryKeyword and CoordinateLexemes instead of BeginGeometry and Coordinate
+type LexemeHandler interface {
+	Handler
+	// BeginGeometryKeyword receives a geometry along with its dimension keyword
+	// as written in the source, dimsKeyword is empty if the source has none
+	// and dims is then inherited from the enclosing geometry or inferred from the coordinates
+	BeginGeometryKeyword(gtype GeometryType, dims Dims, dimsKeyword string) error
+	// CoordinateLexemes receives a coordinate along with the lexemes of its values
+	// as written in the source, lexemes has 2 to 4 elements depending on the dimension
+	CoordinateLexemes(x, y, z, m float64, lexemes []string) error
+}
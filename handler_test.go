@@ -0,0 +1,72 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// traceHandler records the events it receives
+type traceHandler struct {
+	events []string
+}
+
+func (h *traceHandler) BeginGeometry(gtype GeometryType, dims Dims) error {
+	h.events = append(h.events, fmt.Sprintf("begin %s %d", gtype, dims))
+	return nil
+}
+
+func (h *traceHandler) BeginPart() error {
+	h.events = append(h.events, "(")
+	return nil
+}
+
+func (h *traceHandler) Coordinate(x, y, z, m float64) error {
+	h.events = append(h.events, fmt.Sprint(x, y, z, m))
+	return nil
+}
+
+func (h *traceHandler) EndPart() error {
+	h.events = append(h.events, ")")
+	return nil
+}
+
+func (h *traceHandler) EndGeometry() error {
+	h.events = append(h.events, "end")
+	return nil
+}
+
+func Test_parseWith(t *testing.T) {
+	inputs := []string{
+		"POINT EMPTY",
+		"POINT ZM (1 2 3 4)",
+		"LINESTRING M (1 2 3, 4 5 6)",
+		"POLYGON Z ((1 2 3, 4 5 6, 7 8 9, 1 2 3))",
+		"MULTIPOLYGON (((1 2, 3 4, 5 6, 1 2)), ((1 2, 3 4, 5 6, 1 2), (1 2, 3 4, 5 6, 1 2)))",
+	}
+	outputs := [][]string{
+		{"begin Point 0", "end"},
+		{"begin Point 3", "1 2 3 4", "end"},
+		{"begin LineString 2", "1 2 NaN 3", "4 5 NaN 6", "end"},
+		{"begin Polygon 1", "(", "1 2 3 NaN", "4 5 6 NaN", "7 8 9 NaN", "1 2 3 NaN", ")", "end"},
+		{"begin MultiPolygon 0",
+			"(", "(", "1 2 NaN NaN", "3 4 NaN NaN", "5 6 NaN NaN", "1 2 NaN NaN", ")", ")",
+			"(", "(", "1 2 NaN NaN", "3 4 NaN NaN", "5 6 NaN NaN", "1 2 NaN NaN", ")",
+			"(", "1 2 NaN NaN", "3 4 NaN NaN", "5 6 NaN NaN", "1 2 NaN NaN", ")", ")",
+			"end"},
+	}
+
+	for i, str := range inputs {
+		var h traceHandler
+		p := NewParser(strings.NewReader(str))
+		err := p.ParseWith(&h)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(h.events, outputs[i]) {
+			t.Errorf("incorrect events on test %d: %v", i, h.events)
+		}
+	}
+}
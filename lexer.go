@@ -3,9 +3,11 @@ package wkttoorb
 import (
 	"bufio"
 	"bytes"
-	"fmt"
 	"io"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
 )
 
 type tokenType int
@@ -15,8 +17,11 @@ const (
 	LeftParen tokenType = iota
 	RightParen
 	Comma
+	Equal
+	Semicolon
 
 	// Keyword
+	Srid
 	Empty
 	Z
 	M
@@ -29,6 +34,13 @@ const (
 	Multipoint
 	MultilineString
 	MultiPolygon
+	GeometryCollection
+
+	// Extent type
+	Box
+	Box3D
+	Envelope
+	BBox
 
 	// Values
 	Float
@@ -44,12 +56,37 @@ type Token struct {
 	ttype  tokenType
 	lexeme string
 	pos    int
+	// num holds the bytes of a Float token, lexeme is then empty
+	// it points to a buffer reused by the lexer so it is only valid until the next token is scanned
+	num []byte
+}
+
+// size returns the length in bytes of the token in the source
+func (t Token) size() int {
+	return len(t.lexeme) + len(t.num)
+}
+
+// text returns the lexeme of the token, it allocates for Float tokens
+func (t Token) text() string {
+	if t.ttype == Float {
+		return string(t.num)
+	}
+	return t.lexeme
 }
 
 type Lexer struct {
 	reader *bufio.Reader
 
 	pos int
+
+	// nread counts the bytes read so far, reading stops past maxBytes if set
+	nread    int
+	lastSize int
+	maxBytes int
+	overflow bool
+
+	// num is the buffer the numbers are scanned into
+	num []byte
 }
 
 func NewLexer(reader io.Reader) *Lexer {
@@ -60,21 +97,30 @@ func NewLexer(reader io.Reader) *Lexer {
 
 // getToken add a parsed token to the token list
 func (l *Lexer) getToken(ttype tokenType, lexeme string) Token {
-	t := Token{ttype, lexeme, l.pos}
+	t := Token{ttype: ttype, lexeme: lexeme, pos: l.pos}
 	l.pos += len(lexeme)
 	return t
 }
 
 func (l *Lexer) read() rune {
-	ch, _, err := l.reader.ReadRune()
+	ch, size, err := l.reader.ReadRune()
 	if err != nil {
+		l.lastSize = 0
+		return eof
+	}
+	l.lastSize = size
+	l.nread += size
+	if l.maxBytes > 0 && l.nread > l.maxBytes {
+		l.overflow = true
 		return eof
 	}
 	return ch
 }
 
 func (l *Lexer) unread() {
-	_ = l.reader.UnreadRune()
+	if l.reader.UnreadRune() == nil {
+		l.nread -= l.lastSize
+	}
 }
 
 func (l *Lexer) peek() rune {
@@ -87,11 +133,12 @@ func (l *Lexer) peek() rune {
 }
 
 // scanToLowerWord scan a word and returns its value in lower letters
+// words start with a letter and may contain digits, as in box3d
 func (l *Lexer) scanToLowerWord(r rune) string {
 	var buf bytes.Buffer
 	buf.WriteRune(unicode.ToLower(r))
 	r = l.read()
-	for unicode.IsLetter(r) {
+	for unicode.IsLetter(r) || unicode.IsDigit(r) {
 		buf.WriteRune(unicode.ToLower(r))
 		r = l.read()
 	}
@@ -99,37 +146,50 @@ func (l *Lexer) scanToLowerWord(r rune) string {
 	return buf.String()
 }
 
-// scanFloat scan a string representing a float
-func (l *Lexer) scanFloat(r rune) string {
-	var buf bytes.Buffer
-	buf.WriteRune(r)
+// scanFloat scan the bytes representing a float into the num buffer
+func (l *Lexer) scanFloat(r rune) []byte {
+	l.num = utf8.AppendRune(l.num[:0], r)
 	r = l.read()
 	for isFloatRune(r) {
-		buf.WriteRune(r)
+		l.num = utf8.AppendRune(l.num, r)
 		r = l.read()
 	}
 	l.unread()
-	return buf.String()
+	return l.num
 }
 
 // scanToken scans the next lexeme
-// return false is eof is reached true otherwise
-// error is non nil only in case of unexpected character or word
+// error is non nil in case of unexpected character or word
+// or if the input is longer than the maximum size allowed
 func (l *Lexer) scanToken() (Token, error) {
+	t, err := l.scan()
+	if err == nil && l.overflow {
+		return Token{}, errors.Wrapf(ErrLimitExceeded, "input longer than %d bytes", l.maxBytes)
+	}
+	return t, err
+}
+
+func (l *Lexer) scan() (Token, error) {
 	r := l.read()
 	switch {
 	case unicode.IsSpace(r):
-		l.pos++
-		return l.scanToken()
+		l.pos += l.lastSize
+		return l.scan()
 	case r == '(':
 		return l.getToken(LeftParen, "("), nil
 	case r == ')':
 		return l.getToken(RightParen, ")"), nil
 	case r == ',':
 		return l.getToken(Comma, ","), nil
+	case r == '=':
+		return l.getToken(Equal, "="), nil
+	case r == ';':
+		return l.getToken(Semicolon, ";"), nil
 	case unicode.IsLetter(r):
 		w := l.scanToLowerWord(r)
 		switch w {
+		case "srid":
+			return l.getToken(Srid, "srid"), nil
 		case "empty":
 			return l.getToken(Empty, "empty"), nil
 		case "z":
@@ -150,19 +210,38 @@ func (l *Lexer) scanToken() (Token, error) {
 			return l.getToken(MultilineString, "multilinestring"), nil
 		case "multipolygon":
 			return l.getToken(MultiPolygon, "multipolygon"), nil
+		case "geometrycollection":
+			return l.getToken(GeometryCollection, "geometrycollection"), nil
+		case "box":
+			return l.getToken(Box, "box"), nil
+		case "box3d":
+			return l.getToken(Box3D, "box3d"), nil
+		case "envelope":
+			return l.getToken(Envelope, "envelope"), nil
+		case "bbox":
+			return l.getToken(BBox, "bbox"), nil
 		default:
-			return Token{}, fmt.Errorf("Unexpected word %s on character %d", w, l.pos)
+			return Token{}, l.illegal("unexpected word "+w, len(w))
 		}
 	case beginFloat(r):
-		w := l.scanFloat(r)
-		return l.getToken(Float, w), nil
+		t := Token{ttype: Float, pos: l.pos, num: l.scanFloat(r)}
+		l.pos += len(t.num)
+		return t, nil
 	case r == eof:
 		return l.getToken(Eof, ""), nil
 	default:
-		return Token{}, fmt.Errorf("Unexpected rune %s on character %d", string(r), l.pos)
+		return Token{}, l.illegal("unexpected rune "+string(r), l.lastSize)
 	}
 }
 
+// illegal returns the error for the invalid lexeme of size bytes just read
+// and moves pos after it
+func (l *Lexer) illegal(msg string, size int) *ParseError {
+	e := &ParseError{Pos: l.pos, End: l.pos + size, Msg: msg}
+	l.pos += size
+	return e
+}
+
 func beginFloat(r rune) bool {
 	return r == '-' || r == '.' || unicode.IsNumber(r)
 }
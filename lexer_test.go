@@ -19,6 +19,10 @@ func Test_scanToken(t *testing.T) {
 		",",
 		"-23",
 		"5e-05",
+		"SRID",
+		"=",
+		";",
+		"BOX3D",
 	}
 
 	outputs := []Token{
@@ -34,6 +38,10 @@ func Test_scanToken(t *testing.T) {
 		{ttype: Comma, lexeme: ","},
 		{ttype: Float, lexeme: "-23"},
 		{ttype: Float, lexeme: "5e-05"},
+		{ttype: Srid, lexeme: "srid"},
+		{ttype: Equal, lexeme: "="},
+		{ttype: Semicolon, lexeme: ";"},
+		{ttype: Box3D, lexeme: "box3d"},
 	}
 
 	for i, input := range inputs {
@@ -45,7 +53,7 @@ func Test_scanToken(t *testing.T) {
 		if token.ttype != outputs[i].ttype {
 			t.Errorf("incorrect ttype for %s", input)
 		}
-		if token.lexeme != outputs[i].lexeme {
+		if token.text() != outputs[i].lexeme {
 			t.Errorf("incorrect lexeme for %s", input)
 		}
 		if token.pos != 0 {
@@ -0,0 +1,63 @@
+package wkttoorb
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrLimitExceeded is the cause of the error returned when the input
+// goes over one of the Limits given to the parser
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the resources used to parse untrusted input
+// a zero value means no limit
+type Limits struct {
+	// MaxBytes is the maximum size of the input
+	MaxBytes int
+	// MaxCoordinates is the maximum number of coordinates in the geometry
+	MaxCoordinates int
+	// MaxRings is the maximum number of polygon rings in the geometry
+	MaxRings int
+	// MaxParts is the maximum number of members of a multi geometry
+	MaxParts int
+	// MaxDepth is the maximum nesting of geometries
+	MaxDepth int
+}
+
+// WithLimits makes the parser fail with ErrLimitExceeded
+// as soon as the input goes over one of the limits
+func WithLimits(limits Limits) Option {
+	return func(p *Parser) {
+		p.limits = limits
+		p.Lexer.maxBytes = limits.MaxBytes
+	}
+}
+
+// count increments the counter c and checks it against max
+func count(c *int, max int, what string) error {
+	*c++
+	if max > 0 && *c > max {
+		return errors.Wrapf(ErrLimitExceeded, "more than %d %s", max, what)
+	}
+	return nil
+}
+
+func (p *Parser) countCoord() error {
+	return count(&p.coords, p.limits.MaxCoordinates, "coordinates")
+}
+
+func (p *Parser) countRing() error {
+	return count(&p.rings, p.limits.MaxRings, "rings")
+}
+
+func (p *Parser) countPart() error {
+	return count(&p.parts, p.limits.MaxParts, "parts")
+}
+
+// enter must be called when starting to parse a geometry and paired with leave
+func (p *Parser) enter() error {
+	return count(&p.depth, p.limits.MaxDepth, "nested geometries")
+}
+
+func (p *Parser) leave() {
+	p.depth--
+}
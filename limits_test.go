@@ -0,0 +1,60 @@
+package wkttoorb
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func Test_limits(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"LINESTRING (1 2, 3 4, 5 6)",
+		"POLYGON ((1 2, 3 4, 5 6, 1 2), (1 2, 3 4, 5 6, 1 2))",
+		"MULTIPOINT (1 2, 3 4)",
+		"MULTILINESTRING ((1 2, 3 4), (1 2, 3 4))",
+		"MULTIPOLYGON (((1 2, 3 4, 5 6, 1 2)), ((1 2, 3 4, 5 6, 1 2)))",
+	}
+	limits := []Limits{
+		{MaxBytes: 10},
+		{MaxCoordinates: 2},
+		{MaxRings: 1},
+		{MaxParts: 1},
+		{MaxParts: 1},
+		{MaxParts: 1},
+	}
+
+	for i, str := range inputs {
+		if _, err := Scan(str); err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		_, err := Scan(str, WithLimits(limits[i]))
+		if errors.Cause(err) != ErrLimitExceeded {
+			t.Errorf("expected limit error on test %d got %v", i, err)
+		}
+	}
+}
+
+func Test_limitsNotReached(t *testing.T) {
+	limits := Limits{
+		MaxBytes:       len("POLYGON ((1 2, 3 4, 5 6, 1 2))"),
+		MaxCoordinates: 4,
+		MaxRings:       1,
+		MaxParts:       1,
+		MaxDepth:       1,
+	}
+	if _, err := Scan("POLYGON ((1 2, 3 4, 5 6, 1 2))", WithLimits(limits)); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+
+func Test_limitsDepth(t *testing.T) {
+	str := "GEOMETRYCOLLECTION (GEOMETRYCOLLECTION (POINT (1 2)))"
+	if _, err := Scan(str, WithLimits(Limits{MaxDepth: 3})); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	_, err := Scan(str, WithLimits(Limits{MaxDepth: 2}))
+	if errors.Cause(err) != ErrLimitExceeded {
+		t.Errorf("expected limit error got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package wkttoorb
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// PromoteToMulti makes the parser report points, linestrings and polygons,
+// collection members included, as multi geometries with a single member
+// an EMPTY geometry is promoted to an EMPTY multi geometry
+func PromoteToMulti() Option {
+	return func(p *Parser) {
+		p.promote = true
+	}
+}
+
+// promoter is a Handler reporting the single geometries to next as multi geometries
+// it passes the lexemes through when next is a LexemeHandler
+type promoter struct {
+	next Handler
+	// stack holds the geometries being promoted, more than one for collections
+	stack []promoteFrame
+}
+
+type promoteFrame struct {
+	// wrap is true if the content of the geometry is reported as a part
+	// opened once its first event is received
+	wrap, opened bool
+}
+
+// open reports the part holding the content of a promoted geometry
+func (h *promoter) open() error {
+	f := &h.stack[len(h.stack)-1]
+	if !f.wrap || f.opened {
+		return nil
+	}
+	f.opened = true
+	return h.next.BeginPart()
+}
+
+// promote pushes the frame of a geometry and returns the type reported for it
+func (h *promoter) promote(gtype GeometryType) GeometryType {
+	var f promoteFrame
+	switch gtype {
+	case TypePoint:
+		gtype = TypeMultiPoint
+	case TypeLineString:
+		gtype, f.wrap = TypeMultiLineString, true
+	case TypePolygon:
+		gtype, f.wrap = TypeMultiPolygon, true
+	}
+	h.stack = append(h.stack, f)
+	return gtype
+}
+
+func (h *promoter) BeginGeometry(gtype GeometryType, dims Dims) error {
+	return h.next.BeginGeometry(h.promote(gtype), dims)
+}
+
+func (h *promoter) BeginGeometryKeyword(gtype GeometryType, dims Dims, dimsKeyword string) error {
+	if lh, ok := h.next.(LexemeHandler); ok {
+		return lh.BeginGeometryKeyword(h.promote(gtype), dims, dimsKeyword)
+	}
+	return h.BeginGeometry(gtype, dims)
+}
+
+func (h *promoter) BeginPart() error {
+	if err := h.open(); err != nil {
+		return err
+	}
+	return h.next.BeginPart()
+}
+
+func (h *promoter) Coordinate(x, y, z, m float64) error {
+	if err := h.open(); err != nil {
+		return err
+	}
+	return h.next.Coordinate(x, y, z, m)
+}
+
+func (h *promoter) CoordinateLexemes(x, y, z, m float64, lexemes []string) error {
+	lh, ok := h.next.(LexemeHandler)
+	if !ok {
+		return h.Coordinate(x, y, z, m)
+	}
+	if err := h.open(); err != nil {
+		return err
+	}
+	return lh.CoordinateLexemes(x, y, z, m, lexemes)
+}
+
+func (h *promoter) EndPart() error {
+	return h.next.EndPart()
+}
+
+func (h *promoter) EndGeometry() error {
+	f := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+	if f.opened {
+		if err := h.next.EndPart(); err != nil {
+			return err
+		}
+	}
+	return h.next.EndGeometry()
+}
+
+// Explosion iterates over the members of a geometry
+type Explosion struct {
+	// stack holds the geometries being exploded and the index of their next member
+	stack []explodeFrame
+
+	geom  orb.Geometry
+	index int
+}
+
+type explodeFrame struct {
+	geom orb.Geometry
+	next int
+}
+
+// Explode returns an iterator over the members of the multi geometry or collection g
+// the members of nested collections are yielded in place of the collection,
+// any other geometry is yielded as its only member
+//
+//	e := Explode(g)
+//	for e.Next() {
+//		member, index := e.Geometry(), e.Index()
+//	}
+func Explode(g orb.Geometry) *Explosion {
+	return &Explosion{stack: []explodeFrame{{geom: g}}}
+}
+
+// Next moves to the next member, it returns false once all members were yielded
+func (e *Explosion) Next() bool {
+	for len(e.stack) > 0 {
+		f := &e.stack[len(e.stack)-1]
+		i := f.next
+		f.next++
+
+		var member orb.Geometry
+		switch g := f.geom.(type) {
+		case orb.MultiPoint:
+			if i < len(g) {
+				member = g[i]
+			}
+		case orb.MultiLineString:
+			if i < len(g) {
+				member = g[i]
+			}
+		case orb.MultiPolygon:
+			if i < len(g) {
+				member = g[i]
+			}
+		case orb.Collection:
+			if i < len(g) {
+				member = g[i]
+			}
+		default:
+			// the geometry at the bottom of the stack is not a multi geometry
+			if i == 0 && g != nil {
+				member = g
+			}
+		}
+		if member == nil {
+			e.stack = e.stack[:len(e.stack)-1]
+			continue
+		}
+		if c, ok := member.(orb.Collection); ok {
+			e.stack = append(e.stack, explodeFrame{geom: c})
+			continue
+		}
+		e.geom, e.index = member, i
+		return true
+	}
+	return false
+}
+
+// Geometry returns the current member
+func (e *Explosion) Geometry() orb.Geometry {
+	return e.geom
+}
+
+// Index returns the index of the current member in its parent geometry
+func (e *Explosion) Index() int {
+	return e.index
+}
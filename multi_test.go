@@ -0,0 +1,82 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_promoteToMulti(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"POINT EMPTY",
+		"LINESTRING (1 2, 3 4)",
+		"LINESTRING EMPTY",
+		"POLYGON ((0 0, 1 0, 0 0), (1 1, 2 1, 1 1))",
+		"MULTIPOLYGON (((0 0, 1 0, 0 0)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), POLYGON EMPTY)",
+	}
+	outputs := []orb.Geometry{
+		orb.MultiPoint{{1, 2}},
+		orb.MultiPoint{},
+		orb.MultiLineString{{{1, 2}, {3, 4}}},
+		orb.MultiLineString{},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {0, 0}}, {{1, 1}, {2, 1}, {1, 1}}}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {0, 0}}}},
+		orb.Collection{orb.MultiPoint{{1, 2}}, orb.MultiPolygon{}},
+	}
+
+	for i, str := range inputs {
+		g, err := Scan(str, PromoteToMulti())
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_explode(t *testing.T) {
+	inputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {0, 0}}}, {}},
+		orb.Collection{orb.Point{1, 2}, orb.Collection{orb.LineString{{1, 2}, {3, 4}}}, orb.Point{5, 6}},
+		orb.MultiLineString{},
+		nil,
+	}
+	geometries := [][]orb.Geometry{
+		{orb.Point{1, 2}},
+		{orb.Point{1, 2}, orb.Point{3, 4}},
+		{orb.Polygon{{{0, 0}, {1, 0}, {0, 0}}}, orb.Polygon{}},
+		{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}, orb.Point{5, 6}},
+		nil,
+		nil,
+	}
+	indexes := [][]int{
+		{0},
+		{0, 1},
+		{0, 1},
+		{0, 0, 2},
+		nil,
+		nil,
+	}
+
+	for i, g := range inputs {
+		var members []orb.Geometry
+		var index []int
+		e := Explode(g)
+		for e.Next() {
+			members = append(members, e.Geometry())
+			index = append(index, e.Index())
+		}
+		if !reflect.DeepEqual(members, geometries[i]) {
+			t.Errorf("incorrect members returned on test %d: %v", i, members)
+		}
+		if !reflect.DeepEqual(index, indexes[i]) {
+			t.Errorf("incorrect indexes returned on test %d: %v", i, index)
+		}
+	}
+}
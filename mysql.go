@@ -0,0 +1,34 @@
+package wkttoorb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// tryDecodeMySQL decodes data if it is a MySQL geometry, a SRID followed by WKB
+// making up the rest of data, WKB alone does not decode once its first 4 bytes are skipped
+func tryDecodeMySQL(data []byte) (Geometry, bool) {
+	g, err := DecodeMySQL(data)
+	return g, err == nil
+}
+
+// DecodeMySQL decodes a geometry in the MySQL internal format
+// a 4 bytes little endian SRID followed by WKB
+// the coordinates are read as stored, WithAxisOrder applies to MySQL WKT only
+func DecodeMySQL(data []byte) (Geometry, error) {
+	if len(data) < 9 {
+		return Geometry{}, fmt.Errorf("invalid mysql geometry, %d bytes", len(data))
+	}
+	g, _, err := decodeWKB(data[4:])
+	if err != nil {
+		return Geometry{}, errors.Wrap(err, "invalid mysql geometry")
+	}
+	return Geometry{
+		Geometry: g,
+		SRID:     int(binary.LittleEndian.Uint32(data[:4])),
+		Envelope: g.Bound(),
+		Valid:    true,
+	}, nil
+}
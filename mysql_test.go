@@ -0,0 +1,64 @@
+package wkttoorb
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkb"
+)
+
+func Test_decodeMySQL(t *testing.T) {
+	inputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.Polygon{{{0, 0}, {4, 0}, {4, 3}, {0, 0}}},
+		orb.LineString{{1, 2}, {3, 4}},
+	}
+	srids := []uint32{0, 4326, 0}
+
+	for i, input := range inputs {
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, srids[i])
+		data = append(data, wkb.MustMarshal(input, binary.LittleEndian)...)
+
+		expected := Geometry{Geometry: input, SRID: int(srids[i]), Envelope: input.Bound(), Valid: true}
+		g, err := DecodeMySQL(data)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, expected) {
+			t.Errorf("incorrect value returned on test %d: %+v", i, g)
+		}
+
+		var scanned Geometry
+		if err := scanned.Scan(data); err != nil {
+			t.Errorf("unexpected error %s scanning test %d", err, i)
+		}
+		if !reflect.DeepEqual(scanned, expected) {
+			t.Errorf("incorrect value scanned on test %d: %+v", i, scanned)
+		}
+	}
+
+	if _, err := DecodeMySQL([]byte{0xe6, 0x10, 0, 0, 1}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func Test_axisOrder(t *testing.T) {
+	g, err := Scan("LINESTRING (48.85 2.35, 45.76 4.84)", WithAxisOrder(LatLon))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(g, orb.LineString{{2.35, 48.85}, {4.84, 45.76}}) {
+		t.Errorf("incorrect value returned %v", g)
+	}
+
+	g, err = Scan("POINT (1 2)", WithAxisOrder(LonLat))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if g != (orb.Point{1, 2}) {
+		t.Errorf("incorrect value returned %v", g)
+	}
+}
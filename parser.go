@@ -1,373 +1,530 @@
 package wkttoorb
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
 
 	"github.com/paulmach/orb"
-	"github.com/pkg/errors"
 )
 
+// Parser parses WKT geometries read from its Lexer
+// create parsers with NewParser, the options configure them
 type Parser struct {
 	*Lexer
+	// buf holds a token put back by unscan when buffered is set
+	buf      Token
+	buffered bool
+	// end is the offset after the last token consumed
+	end, prevEnd int
+	// ords and toks hold the values of the coordinate being parsed,
+	// they are kept here so that parsing a coordinate does not allocate
+	ords [4]float64
+	toks [4]Token
+
+	h    Handler
+	srid int
+	// dims is the dimension of the geometry being parsed
+	// when dimsSet is false it is not known yet and the events
+	// are held in pending until the first coordinate is read
+	dims    Dims
+	dimsSet bool
+	pending []func(Dims) error
+
+	transform TransformZM
+	dropEmpty bool
+	// filters are applied to the coordinates of each line and ring
+	// simplify is applied first, by windows as the coordinates are read
+	filters  []filterFunc
+	simplify filterFunc
+	// clip is the bound set by ScanClipped, it requires promote
+	clip    *orb.Bound
+	promote bool
+	// keepLexemes is set by KeepLexemes, lh is then the handler if it is a LexemeHandler
+	keepLexemes bool
+	lh          LexemeHandler
+
+	limits Limits
+	// gtype is the type of the geometry being parsed
+	gtype                       GeometryType
+	coords, rings, parts, depth int
+
+	ctx   context.Context
+	ticks int
+
+	// tracer receives the source positions of the syntax elements, it is nil
+	// unless a syntax tree is built
+	tracer tracer
+
+	// recovering is set by WithRecovery, errs holds the errors recovered from
+	recovering bool
+	errs       ErrorList
+
+	// optErr is the error of an invalid option, returned by every parse
+	optErr error
 }
 
-func (p *Parser) Parse() (orb.Geometry, error) {
-	t, err := p.scanToken()
-	if err != nil {
-		return nil, err
+// Option configures optional behaviour of a Parser
+type Option func(*Parser)
+
+// NewParser returns a parser reading from reader configured with opts
+func NewParser(reader io.Reader, opts ...Option) *Parser {
+	p := &Parser{Lexer: NewLexer(reader)}
+	for _, opt := range opts {
+		opt(p)
 	}
-	switch t.ttype {
-	case Point:
-		return p.parsePoint()
-	case Linestring:
-		return p.parseLineString()
-	case Polygon:
-		return p.parsePolygon()
-	case Multipoint:
-		line, err := p.parseLineString()
-		return orb.MultiPoint(line), err
-	case MultilineString:
-		poly, err := p.parsePolygon()
-		multiline := make(orb.MultiLineString, 0, len(poly))
-		for _, ring := range poly {
-			multiline = append(multiline, orb.LineString(ring))
-		}
-		return multiline, err
-	case MultiPolygon:
-		return p.parseMultiPolygon()
-	default:
-		return nil, fmt.Errorf("Parse unexpected token %s on pos %d expected geometry type", t.lexeme, t.pos)
+	return p
+}
+
+// KeepLexemes makes the parser hand the source text of the coordinate values and dimension keywords
+// to handlers implementing LexemeHandler, they are passed through PromoteToMulti
+// it has no effect with a transform, a grid size, a simplification or a clipping bound
+// as the lexemes would not match the rewritten coordinates
+func KeepLexemes() Option {
+	return func(p *Parser) {
+		p.keepLexemes = true
 	}
 }
 
-func (p *Parser) parsePoint() (point orb.Point, err error) {
-	t, err := p.scanToken()
-	if err != nil {
-		return point, err
+// DropEmpty makes the parser skip the EMPTY members of multi geometries,
+// polygons and collections instead of reporting them
+func DropEmpty() Option {
+	return func(p *Parser) {
+		p.dropEmpty = true
 	}
-	switch t.ttype {
-	case Empty:
-		point = orb.Point{0, 0}
-	case Z, M, ZM:
-		t1, err := p.scanToken()
-		if err != nil {
-			return point, err
-		}
-		if t1.ttype == Empty {
-			point = orb.Point{0, 0}
-			break
-		}
-		if t1.ttype != LeftParen {
-			return point, fmt.Errorf("parse point unexpected token %s on pos %d", t.lexeme, t.pos)
-		}
-		fallthrough
-	case LeftParen:
-		switch t.ttype { // reswitch on the type because of the fallthrough
-		case Z, M:
-			point, err = p.parseCoordDrop1()
-		case ZM:
-			point, err = p.parseCoordDrop2()
-		default:
-			point, err = p.parseCoord()
-		}
-		if err != nil {
-			return point, err
-		}
+}
 
-		t, err = p.scanToken()
-		if err != nil {
-			return point, err
+// Parse parses a geometry into an orb geometry
+// in recovery mode the geometry is returned along with the ErrorList
+func (p *Parser) Parse() (orb.Geometry, error) {
+	var b OrbBuilder
+	if err := p.ParseWith(&b); err != nil {
+		if _, ok := err.(ErrorList); ok {
+			return b.Geometry(), err
 		}
+		return nil, err
+	}
+	return b.Geometry(), nil
+}
 
-		if t.ttype != RightParen {
-			return point, fmt.Errorf("parse point unexpected token %s on pos %d expected )", t.lexeme, t.pos)
-		}
-	default:
-		return point, fmt.Errorf("parse point unexpected token %s on pos %d", t.lexeme, t.pos)
+// ParseWith parses a geometry sending the parsing events to h
+// in recovery mode the syntax errors are returned as an ErrorList
+// once the whole input is read
+func (p *Parser) ParseWith(h Handler) error {
+	if err := p.reset(h); err != nil {
+		return err
+	}
+	err := p.parseSRID()
+	if err == nil {
+		err = p.parseGeometry()
+	}
+	if err == nil {
+		err = p.expectEof()
 	}
+	return p.recovered(err)
+}
 
-	t, err = p.scanToken()
+// expectEof returns an error if the input does not end after the geometry
+func (p *Parser) expectEof() error {
+	t, err := p.scanToken()
 	if err != nil {
-		return point, err
+		return err
 	}
 	if t.ttype != Eof {
-		return point, fmt.Errorf("parse point unexpected token %s on pos %d", t.lexeme, t.pos)
+		return p.unexpected(t, "end of input")
 	}
+	return nil
+}
 
-	return point, nil
+// reset prepares the parser to parse a new geometry sending its events to h
+// the limits apply to each geometry separately
+// it returns the error of an invalid option
+func (p *Parser) reset(h Handler) error {
+	// the part filter receives the promoted geometries, so that a clipped line
+	// can be split into several members
+	if len(p.filters) > 0 || p.simplify != nil || p.clip != nil {
+		h = &partFilter{next: h, filters: p.filters, simplify: p.simplify, clip: p.clip}
+	}
+	if p.promote {
+		h = &promoter{next: h}
+	}
+	p.h, p.srid = h, 0
+	p.lh = nil
+	if lh, ok := h.(LexemeHandler); ok && p.keepLexemes {
+		p.lh = lh
+	}
+	p.dims, p.dimsSet, p.pending = XY, false, p.pending[:0]
+	p.coords, p.rings, p.parts = 0, 0, 0
+	p.errs = nil
+	p.Lexer.nread = 0
+	return p.optErr
 }
 
-func (p *Parser) parseLineString() (line orb.LineString, err error) {
-	line = make([]orb.Point, 0)
+// scanToken returns the token put back by unscan if any or scans the next one
+func (p *Parser) scanToken() (t Token, err error) {
+	if p.buffered {
+		t, p.buffered = p.buf, false
+	} else {
+		t, err = p.Lexer.scanToken()
+	}
+	p.prevEnd, p.end = p.end, t.pos+t.size()
+	return t, err
+}
+
+// unscan puts back the last token scanned to be returned by the next call to scanToken
+func (p *Parser) unscan(t Token) {
+	p.buf, p.buffered = t, true
+	p.end = p.prevEnd
+}
+
+// SRID returns the SRID given by the EWKT prefix of the last geometry parsed
+// or 0 if it had none
+func (p *Parser) SRID() int {
+	return p.srid
+}
+
+// parseSRID parses the optional EWKT prefix SRID=<srid>;
+func (p *Parser) parseSRID() error {
 	t, err := p.scanToken()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	switch t.ttype {
-	case Empty:
-	case Z, M, ZM:
-		t1, err := p.scanToken()
-		if err != nil {
-			return line, err
-		}
-		if t1.ttype == Empty {
-			break
-		}
-		if t1.ttype != LeftParen {
-			return line, fmt.Errorf("unexpected token %s on pos %d expected '('", t.lexeme, t.pos)
-		}
-		fallthrough
-	case LeftParen:
-		line, err = p.parseLineStringText(t.ttype)
-		if err != nil {
-			return line, err
-		}
-	default:
-		return line, fmt.Errorf("unexpected token %s on pos %d", t.lexeme, t.pos)
+	if t.ttype != Srid {
+		p.unscan(t)
+		return nil
 	}
 
 	t, err = p.scanToken()
 	if err != nil {
-		return line, err
+		return err
 	}
-	if t.ttype != Eof {
-		return line, fmt.Errorf("unexpected token %s on pos %d, expected Eof", t.lexeme, t.pos)
+	if t.ttype != Equal {
+		return p.unexpected(t, "'='")
 	}
-
-	return line, nil
-}
-
-func (p *Parser) parseLineStringText(ttype tokenType) (line orb.LineString, err error) {
-	line = make([]orb.Point, 0)
-	for {
-		var point orb.Point
-		switch ttype {
-		case Z, M:
-			point, err = p.parseCoordDrop1()
-		case ZM:
-			point, err = p.parseCoordDrop2()
-		default:
-			point, err = p.parseCoord()
-		}
-		if err != nil {
-			return line, err
-		}
-		line = append(line, point)
-		t, err := p.scanToken()
-		if err != nil {
-			return line, err
-		}
-		if t.ttype == RightParen {
-			break
-		} else if t.ttype != Comma {
-			return line, fmt.Errorf("unexpected token %s on pos %d expected ','", t.lexeme, t.pos)
-		}
+	t, err = p.scanToken()
+	if err != nil {
+		return err
 	}
-	return line, nil
+	if t.ttype != Float {
+		return p.unexpected(t, "integer")
+	}
+	p.srid, err = strconv.Atoi(t.text())
+	if err != nil {
+		return &ParseError{Pos: t.pos, End: p.end, Msg: "invalid srid " + t.text()}
+	}
+	t, err = p.scanToken()
+	if err != nil {
+		return err
+	}
+	if t.ttype != Semicolon {
+		return p.unexpected(t, "';'")
+	}
+	return nil
 }
 
-func (p *Parser) parsePolygon() (poly orb.Polygon, err error) {
-	poly = make([]orb.Ring, 0)
+// parseGeometry parses a geometry tagged text
+func (p *Parser) parseGeometry() error {
 	t, err := p.scanToken()
 	if err != nil {
-		return poly, err
+		return err
 	}
-	switch t.ttype {
-	case Empty:
-	case Z, M, ZM:
-		t1, err := p.scanToken()
-		if err != nil {
-			return poly, err
-		}
-		if t1.ttype == Empty {
-			break
-		}
-		if t1.ttype != LeftParen {
-			return poly, fmt.Errorf("unexpected token %s on pos %d expected '('", t.lexeme, t.pos)
-		}
-		fallthrough
-	case LeftParen:
-		poly, err = p.parsePolygonText(t.ttype)
-		if err != nil {
-			return poly, err
-		}
-	default:
-		return poly, fmt.Errorf("unexpected token %s on pos %d", t.lexeme, t.pos)
+	gtype, ok := geometryTypes[t.ttype]
+	if !ok {
+		return p.unexpected(t, "geometry type")
 	}
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+	defer func(gtype GeometryType) { p.gtype = gtype }(p.gtype)
+	p.gtype = gtype
 
+	keyword := t
 	t, err = p.scanToken()
 	if err != nil {
-		return poly, err
-	}
-	if t.ttype != Eof {
-		return poly, fmt.Errorf("unexpected token %s on pos %d, expected Eof", t.lexeme, t.pos)
+		return err
 	}
-
-	return poly, nil
-}
-
-func (p *Parser) parsePolygonText(ttype tokenType) (poly orb.Polygon, err error) {
-	poly = make([]orb.Ring, 0)
-	for {
-		var line orb.LineString
-		t, err := p.scanToken()
-		if err != nil {
-			return poly, err
+	var dimsKeyword *Token
+	if d, ok := dimensions[t.ttype]; ok {
+		if err := p.explicitDims(t, d); err != nil {
+			return err
 		}
-		if t.ttype != LeftParen {
-			return poly, fmt.Errorf("unexpected token %s on pos %d expected '('", t.lexeme, t.pos)
-		}
-		line, err = p.parseLineStringText(ttype)
-		if err != nil {
-			return poly, err
-		}
-		poly = append(poly, orb.Ring(line))
+		kw := t
+		dimsKeyword = &kw
 		t, err = p.scanToken()
 		if err != nil {
-			return poly, err
+			return err
 		}
-		if t.ttype == RightParen {
-			break
-		} else if t.ttype != Comma {
-			return poly, fmt.Errorf("unexpected token %s on pos %d expected ','", t.lexeme, t.pos)
+	}
+
+	if t.ttype != Empty && t.ttype != LeftParen {
+		return p.unexpected(t, "'('", "empty")
+	}
+	if t.ttype == Empty && p.dropEmpty && p.depth > 1 {
+		return nil
+	}
+	if p.tracer != nil {
+		p.tracer.beginGeometry(keyword, dimsKeyword)
+	}
+	kw := ""
+	if dimsKeyword != nil {
+		kw = dimsKeyword.lexeme
+	}
+	if err := p.beginGeometry(gtype, kw); err != nil {
+		return err
+	}
+	if t.ttype == LeftParen {
+		switch gtype {
+		case TypePoint:
+			err = p.parsePointText()
+		case TypeLineString:
+			err = p.parseLineStringText()
+		case TypeMultiPoint:
+			err = p.parseMultiPointText()
+		case TypePolygon, TypeMultiLineString:
+			err = p.parsePolygonText()
+		case TypeMultiPolygon:
+			err = p.parseMultiPolygonText()
+		case TypeGeometryCollection:
+			err = p.parseCollectionText()
+		}
+		if err != nil {
+			return err
 		}
 	}
-	return poly, nil
+	if p.tracer != nil {
+		p.tracer.endGeometry(p.end)
+	}
+	return p.endGeometry()
 }
 
-func (p *Parser) parseMultiPolygon() (multi orb.MultiPolygon, err error) {
-	multi = make([]orb.Polygon, 0)
+// parsePointText parses the coordinate of a point up to the closing paren
+func (p *Parser) parsePointText() error {
+	if err := p.resync(p.parseCoord()); err != nil {
+		return err
+	}
+
 	t, err := p.scanToken()
 	if err != nil {
-		return multi, err
+		return p.resync(err)
 	}
-	switch t.ttype {
-	case Empty:
-	case Z, M, ZM:
-		t1, err := p.scanToken()
-		if err != nil {
-			return multi, err
+	if t.ttype != RightParen {
+		return p.resync(p.unexpected(t, "')'"))
+	}
+	return nil
+}
+
+// parseList parses the elements of a list with parseElem up to the closing paren
+// in recovery mode an invalid element is skipped up to the next ',' or ')'
+func (p *Parser) parseList(parseElem func() error) error {
+	for {
+		if err := p.checkContext(); err != nil {
+			return err
 		}
-		if t1.ttype == Empty {
-			break
+		if err := p.resync(parseElem()); err != nil {
+			return err
 		}
-		if t1.ttype != LeftParen {
-			return multi, fmt.Errorf("unexpected token %s on pos %d expected '('", t.lexeme, t.pos)
+
+		t, err := p.scanToken()
+		if err == nil && t.ttype != Comma && t.ttype != RightParen {
+			err = p.unexpected(t, "','", "')'")
 		}
-		fallthrough
-	case LeftParen:
-		multi, err = p.parseMultiPolygonText(t.ttype)
 		if err != nil {
-			return multi, err
+			if err := p.resync(err); err != nil {
+				return err
+			}
+			// resync stops before ',' or ')' or on the end of the input
+			if t, err = p.scanToken(); err != nil {
+				return err
+			}
+		}
+		switch t.ttype {
+		case Comma:
+		case RightParen:
+			return nil
+		default:
+			p.unscan(t)
+			return nil
 		}
-	default:
-		return multi, fmt.Errorf("unexpected token %s on pos %d", t.lexeme, t.pos)
-	}
-
-	t, err = p.scanToken()
-	if err != nil {
-		return multi, err
-	}
-	if t.ttype != Eof {
-		return multi, fmt.Errorf("unexpected token %s on pos %d, expected Eof", t.lexeme, t.pos)
 	}
+}
 
-	return multi, nil
+// parseLineStringText parses a list of coordinates up to the closing paren
+func (p *Parser) parseLineStringText() error {
+	return p.parseList(p.parseCoord)
 }
 
-func (p *Parser) parseMultiPolygonText(ttype tokenType) (multi orb.MultiPolygon, err error) {
-	multi = make([]orb.Polygon, 0)
-	for {
-		var poly orb.Polygon
+// parsePolygonText parses a list of rings up to the closing paren
+// it is also used for the lines of a multilinestring
+func (p *Parser) parsePolygonText() error {
+	return p.parseList(func() error {
 		t, err := p.scanToken()
 		if err != nil {
-			return multi, err
+			return err
 		}
-		if t.ttype != LeftParen {
-			return multi, fmt.Errorf("unexpected token %s on pos %d expected '('", t.lexeme, t.pos)
+		if t.ttype != LeftParen && t.ttype != Empty {
+			return p.unexpected(t, "'('", "empty")
 		}
-		poly, err = p.parsePolygonText(ttype)
-		if err != nil {
-			return multi, err
+		if p.gtype == TypeMultiLineString {
+			err = p.countPart()
+		} else {
+			err = p.countRing()
 		}
-		multi = append(multi, poly)
-		t, err = p.scanToken()
 		if err != nil {
-			return multi, err
+			return err
 		}
-		if t.ttype == RightParen {
-			break
-		} else if t.ttype != Comma {
-			return multi, fmt.Errorf("unexpected token %s on pos %d expected ','", t.lexeme, t.pos)
-		}
-	}
-	return multi, nil
+		return p.parsePart(t, p.parseLineStringText)
+	})
 }
 
-func (p *Parser) parseCoord() (point orb.Point, err error) {
-	t1, err := p.scanToken()
-	if err != nil {
-		return point, err
+// parsePart parses a member of a multi geometry or a polygon ring
+// t is its first token, either empty or '(' followed by the text parsed by parseText
+func (p *Parser) parsePart(t Token, parseText func() error) error {
+	if t.ttype == Empty && p.dropEmpty {
+		return nil
 	}
-	if t1.ttype != Float {
-		return point, fmt.Errorf("parse coordinates unexpected token %s on pos %d", t1.lexeme, t1.pos)
+	if err := p.beginPart(); err != nil {
+		return err
 	}
-	t2, err := p.scanToken()
-	if err != nil {
-		return point, err
+	if p.tracer != nil {
+		p.tracer.beginPart(t.pos)
 	}
-	if t2.ttype != Float {
-		return point, fmt.Errorf("parse coordinates unexpected token %s on pos %d", t1.lexeme, t2.pos)
+	if t.ttype == LeftParen {
+		if err := parseText(); err != nil {
+			return err
+		}
 	}
-
-	c1, err := strconv.ParseFloat(t1.lexeme, 64)
-	if err != nil {
-		return point, errors.Wrap(err, fmt.Sprintf("invalid lexeme %s for token on pos %d", t1.lexeme, t1.pos))
+	if p.tracer != nil {
+		p.tracer.endPart(p.end)
 	}
-	c2, err := strconv.ParseFloat(t2.lexeme, 64)
-	if err != nil {
-		return point, errors.Wrap(err, fmt.Sprintf("invalid lexeme %s for token on pos %d", t2.lexeme, t2.pos))
-	}
-
-	return orb.Point{c1, c2}, nil
+	return p.endPart()
 }
 
-func (p *Parser) parseCoordDrop1() (point orb.Point, err error) {
-	point, err = p.parseCoord()
-	if err != nil {
-		return point, err
-	}
-
-	// drop the last value Z or M coordinates are not really supported
-	t, err := p.scanToken()
-	if err != nil {
-		return point, err
-	}
-	if t.ttype != Float {
-		return point, fmt.Errorf("parseCoordDrop1 unexpected token %s on pos %d expected Float", t.lexeme, t.pos)
-	}
+// parseMultiPointText parses a list of points up to the closing paren
+// each point is either a coordinate, a coordinate between parens or empty
+func (p *Parser) parseMultiPointText() error {
+	return p.parseList(func() error {
+		t, err := p.scanToken()
+		if err != nil {
+			return err
+		}
+		switch t.ttype {
+		case Empty:
+			err = p.parsePart(t, nil)
+		case LeftParen:
+			// the parens are a part of the tree only, the handler receives the coordinate alone
+			if p.tracer != nil {
+				p.tracer.beginPart(t.pos)
+			}
+			err = p.parsePointText()
+			if err == nil && p.tracer != nil {
+				p.tracer.endPart(p.end)
+			}
+		default:
+			p.unscan(t)
+			err = p.parseCoord()
+		}
+		if err != nil {
+			return err
+		}
+		return p.countPart()
+	})
+}
 
-	return point, nil
+// parseMultiPolygonText parses a list of polygons up to the closing paren
+func (p *Parser) parseMultiPolygonText() error {
+	return p.parseList(func() error {
+		t, err := p.scanToken()
+		if err != nil {
+			return err
+		}
+		if t.ttype != LeftParen && t.ttype != Empty {
+			return p.unexpected(t, "'('", "empty")
+		}
+		if err := p.countPart(); err != nil {
+			return err
+		}
+		return p.parsePart(t, p.parsePolygonText)
+	})
 }
 
-func (p *Parser) parseCoordDrop2() (point orb.Point, err error) {
-	point, err = p.parseCoord()
-	if err != nil {
-		return point, err
-	}
+// parseCollectionText parses a list of geometries up to the closing paren
+func (p *Parser) parseCollectionText() error {
+	return p.parseList(func() error {
+		if err := p.countPart(); err != nil {
+			return err
+		}
+		return p.parseGeometry()
+	})
+}
 
-	// drop the last value M values
-	// and Z coordinates are not really supported
-	for i := 0; i < 2; i++ {
+// parseCoord parses a coordinate and hands it to the handler after applying
+// the parser transform, missing Z and M values are NaN
+// when the dimension is not known yet it is inferred from the number of values
+func (p *Parser) parseCoord() error {
+	ords, toks := &p.ords, &p.toks
+	var lexemes [4]string
+	n, pos := 0, 0
+	for n < 4 {
 		t, err := p.scanToken()
 		if err != nil {
-			return point, err
+			return err
+		}
+		if n == 0 {
+			pos = t.pos
 		}
 		if t.ttype != Float {
-			return point, fmt.Errorf("parseCoordDrop2 unexpected token %s on pos %d expected Float", t.lexeme, t.pos)
+			if n < 2 {
+				return p.unexpected(t, "number")
+			}
+			p.unscan(t)
+			break
+		}
+		ords[n], err = strconv.ParseFloat(string(t.num), 64)
+		if err != nil {
+			return &ParseError{Pos: t.pos, End: p.end, Msg: "invalid number " + t.text()}
+		}
+		if p.lh != nil && p.transform == nil {
+			// the bytes of t are overwritten by the next token
+			lexemes[n] = t.text()
+		}
+		toks[n] = t
+		n++
+	}
+
+	if !p.dimsSet {
+		if err := p.flush(inferDims(n)); err != nil {
+			return err
+		}
+	} else if n != p.dims.ordinates() {
+		return &ParseError{
+			Pos: pos,
+			End: p.end,
+			Msg: fmt.Sprintf("mixed dimensions, coordinate has %d values expected %d", n, p.dims.ordinates()),
 		}
 	}
+	if err := p.countCoord(); err != nil {
+		return err
+	}
+	if p.tracer != nil {
+		p.tracer.coordinate(toks[:n], ords[:n])
+	}
 
-	return point, nil
+	point, z, m := orb.Point{ords[0], ords[1]}, math.NaN(), math.NaN()
+	switch p.dims {
+	case XYZ:
+		z = ords[2]
+	case XYM:
+		m = ords[2]
+	case XYZM:
+		z, m = ords[2], ords[3]
+	}
+	if p.transform != nil {
+		point, z, m = p.transform(point, z, m)
+	} else if p.lh != nil {
+		return p.lh.CoordinateLexemes(point[0], point[1], z, m, append([]string(nil), lexemes[:n]...))
+	}
+	return p.h.Coordinate(point[0], point[1], z, m)
 }
@@ -3,6 +3,7 @@ package wkttoorb
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/paulmach/orb"
@@ -229,3 +230,116 @@ func Test_parseMultiPolygon(t *testing.T) {
 		}
 	}
 }
+
+func Test_parseGeometryCollection(t *testing.T) {
+	inputs := []string{
+		"geometrycollection empty",
+		"geometrycollection z empty",
+		"geometrycollection (point (1 2), linestring (1 2, 3 4))",
+		"geometrycollection z (point (1 2 3), polygon z ((1 2 3, 4 5 6, 7 8 9, 1 2 3)))",
+		"geometrycollection (point empty, geometrycollection (multipoint (1 2, 3 4)))",
+	}
+	outputs := []orb.Collection{
+		orb.Collection{},
+		orb.Collection{},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}},
+		orb.Collection{orb.Point{1, 2}, orb.Polygon{{{1, 2}, {4, 5}, {7, 8}, {1, 2}}}},
+		orb.Collection{orb.Point{0, 0}, orb.Collection{orb.MultiPoint{{1, 2}, {3, 4}}}},
+	}
+
+	for i, str := range inputs {
+		geo, err := Scan(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+			fmt.Println(geo)
+		}
+	}
+}
+
+func Test_parseEmptyMembers(t *testing.T) {
+	inputs := []string{
+		"MULTIPOLYGON (EMPTY, ((0 0, 1 0, 1 1, 0 0)))",
+		"MULTILINESTRING ((1 2, 3 4), EMPTY)",
+		"POLYGON ((0 0, 1 0, 1 1, 0 0), EMPTY)",
+		"MULTIPOINT (EMPTY, (1 2), 3 4)",
+		"GEOMETRYCOLLECTION (POINT EMPTY, LINESTRING EMPTY, POINT (1 2))",
+		"MULTIPOLYGON Z (EMPTY, ((0 0 1, 1 0 1, 1 1 1, 0 0 1)))",
+	}
+	outputs := []orb.Geometry{
+		orb.MultiPolygon{{}, {{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}, {}},
+		orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}, {}},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.Collection{orb.Point{0, 0}, orb.LineString{}, orb.Point{1, 2}},
+		orb.MultiPolygon{{}, {{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+	}
+	dropped := []orb.Geometry{
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}},
+		orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.Collection{orb.Point{1, 2}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+	}
+
+	for i, str := range inputs {
+		geo, err := Scan(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+			fmt.Println(geo)
+		}
+
+		geo, err = Scan(str, DropEmpty())
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, dropped[i]) {
+			t.Errorf("incorrect value returned with DropEmpty on test %d", i)
+			fmt.Println(geo)
+		}
+	}
+}
+
+func Test_parseEWKT(t *testing.T) {
+	inputs := []string{
+		"SRID=4326;POINT (1 2)",
+		"srid=3857; linestring (1 2, 3 4)",
+		"POINT (1 2)",
+	}
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.Point{1, 2},
+	}
+	srids := []int{4326, 3857, 0}
+
+	for i, str := range inputs {
+		p := NewParser(strings.NewReader(str))
+		geo, err := p.Parse()
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+		}
+		if p.SRID() != srids[i] {
+			t.Errorf("incorrect srid returned on test %d", i)
+		}
+	}
+
+	for i, str := range []string{"SRID=4326 POINT (1 2)", "SRID=;POINT (1 2)", "SRID=4.5;POINT (1 2)"} {
+		if _, err := Scan(str); err == nil {
+			t.Errorf("expected error on invalid srid %d", i)
+		}
+	}
+}
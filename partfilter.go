@@ -0,0 +1,275 @@
+package wkttoorb
+
+import (
+	"github.com/paulmach/orb"
+)
+
+// coord is a buffered coordinate with its Z and M values
+type coord struct {
+	point orb.Point
+	z, m  float64
+}
+
+// filterFunc rewrites the coordinates of a line or of a ring
+type filterFunc func(coords []coord, ring bool) []coord
+
+// partFilter is a Handler buffering the coordinates of each line and ring
+// and passing them through the filters before forwarding them to next
+// simplify is applied as the coordinates are buffered, see compact
+// clip drops the points outside of it and clips the lines and rings, splitting
+// the lines crossing it so it requires the geometries to be promoted
+// the lines left with less than 2 coordinates and the rings left with less than 4 are dropped,
+// along with the holes of a polygon whose exterior ring is dropped,
+// the EMPTY parts of the input are kept
+type partFilter struct {
+	next     Handler
+	filters  []filterFunc
+	simplify filterFunc
+	clip     *orb.Bound
+
+	stack []filterFrame
+	// buf is the coordinate buffer reused by the successive lines and rings
+	buf []coord
+	// points and clipped are the buffers reused by the clipping
+	points  []orb.Point
+	clipped []coord
+}
+
+// filterFrame is the state of a geometry or a part being filtered
+type filterFrame struct {
+	gtype GeometryType
+	// depth is 0 for a geometry and the nesting of the part within it otherwise
+	depth int
+	// index is the position of a part in its parent
+	index    int
+	children int
+	// emitted is true once BeginPart has been forwarded, parts are forwarded
+	// only once they have content so that collapsed parts can be dropped
+	emitted bool
+	// skip is set on the holes of a polygon whose exterior ring was dropped
+	skip            bool
+	exteriorDropped bool
+	// dropped is set once a point of the part is clipped out
+	dropped bool
+
+	coords []coord
+	// simplified is the number of leading coords already simplified
+	simplified int
+}
+
+// leaf returns true if the coordinates of f are a line or a ring to filter
+func (f *filterFrame) leaf() bool {
+	switch f.gtype {
+	case TypeLineString:
+		return f.depth == 0
+	case TypePolygon, TypeMultiLineString:
+		return f.depth == 1
+	case TypeMultiPolygon:
+		return f.depth == 2
+	default:
+		return false
+	}
+}
+
+// ring returns true if f is the ring of a polygon
+func (f *filterFrame) ring() bool {
+	return f.gtype == TypePolygon || f.gtype == TypeMultiPolygon
+}
+
+// rings returns true if the children of f are rings
+func (f *filterFrame) rings() bool {
+	return (f.gtype == TypePolygon && f.depth == 0) || (f.gtype == TypeMultiPolygon && f.depth == 1)
+}
+
+func (h *partFilter) top() *filterFrame {
+	return &h.stack[len(h.stack)-1]
+}
+
+// emit forwards the BeginPart events of the parts being filtered not forwarded yet
+func (h *partFilter) emit() error {
+	for i := range h.stack {
+		if f := &h.stack[i]; !f.emitted {
+			if err := h.next.BeginPart(); err != nil {
+				return err
+			}
+			f.emitted = true
+		}
+	}
+	return nil
+}
+
+func (h *partFilter) BeginGeometry(gtype GeometryType, dims Dims) error {
+	if len(h.stack) > 0 {
+		if err := h.emit(); err != nil {
+			return err
+		}
+	}
+	f := filterFrame{gtype: gtype, emitted: true}
+	if f.leaf() {
+		f.coords = h.buf[:0]
+	}
+	h.stack = append(h.stack, f)
+	return h.next.BeginGeometry(gtype, dims)
+}
+
+func (h *partFilter) BeginPart() error {
+	parent := h.top()
+	f := filterFrame{
+		gtype: parent.gtype,
+		depth: parent.depth + 1,
+		index: parent.children,
+		skip:  parent.skip || parent.exteriorDropped,
+	}
+	parent.children++
+	if f.leaf() {
+		f.coords = h.buf[:0]
+	}
+	h.stack = append(h.stack, f)
+	return nil
+}
+
+func (h *partFilter) Coordinate(x, y, z, m float64) error {
+	f := h.top()
+	if f.skip {
+		return nil
+	}
+	if f.leaf() {
+		f.coords = append(f.coords, coord{orb.Point{x, y}, z, m})
+		if h.simplify != nil && len(f.coords)-f.simplified >= simplifyWindow {
+			h.compact(f, false)
+		}
+		return nil
+	}
+	if h.clip != nil && !h.clip.Contains(orb.Point{x, y}) {
+		f.dropped = true
+		return nil
+	}
+	if err := h.emit(); err != nil {
+		return err
+	}
+	return h.next.Coordinate(x, y, z, m)
+}
+
+// compact simplifies the coordinates of f buffered since the last call in place,
+// the window starts at the last point simplified so that the windows join
+func (h *partFilter) compact(f *filterFrame, ring bool) {
+	start := f.simplified - 1
+	if start < 0 {
+		start = 0
+	}
+	out := h.simplify(f.coords[start:], ring)
+	f.coords = f.coords[:start+len(out)]
+	f.simplified = len(f.coords)
+}
+
+// flush filters the coordinates of the leaf f, it returns false if f collapsed
+func (h *partFilter) flush(f *filterFrame) (bool, error) {
+	// the buffer is only reused by the next leaf, once coords are forwarded
+	h.buf = f.coords[:0]
+	if len(f.coords) == 0 {
+		// EMPTY in the input
+		return true, nil
+	}
+	if h.simplify != nil {
+		// a ring simplified in a single window is simplified as a ring
+		h.compact(f, f.ring() && f.simplified == 0)
+	}
+	coords := f.coords
+	for _, filter := range h.filters {
+		coords = filter(coords, f.ring())
+	}
+	if h.clip != nil && !f.ring() {
+		return h.flushPieces(f, h.clipLine(coords))
+	} else if h.clip != nil {
+		coords = h.clipRing(coords)
+	}
+	min := 2
+	if f.ring() {
+		min = 4
+	}
+	if len(coords) < min {
+		return false, nil
+	}
+
+	if err := h.emit(); err != nil {
+		return false, err
+	}
+	for _, c := range coords {
+		if err := h.next.Coordinate(c.point[0], c.point[1], c.z, c.m); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// flushPieces forwards the pieces of the clipped line f as separate parts
+// it returns false if no piece is left
+func (h *partFilter) flushPieces(f *filterFrame, pieces [][]coord) (bool, error) {
+	kept := false
+	for _, coords := range pieces {
+		if len(coords) < 2 {
+			continue
+		}
+		if kept {
+			// the previous piece is closed and the part of f begun again
+			if err := h.next.EndPart(); err != nil {
+				return false, err
+			}
+			f.emitted = false
+		}
+		if err := h.emit(); err != nil {
+			return false, err
+		}
+		for _, c := range coords {
+			if err := h.next.Coordinate(c.point[0], c.point[1], c.z, c.m); err != nil {
+				return false, err
+			}
+		}
+		kept = true
+	}
+	return kept, nil
+}
+
+func (h *partFilter) EndPart() error {
+	f := h.top()
+	if f.skip {
+		h.stack = h.stack[:len(h.stack)-1]
+		return nil
+	}
+
+	if f.leaf() {
+		kept, err := h.flush(f)
+		if err != nil {
+			return err
+		}
+		if !kept {
+			h.stack = h.stack[:len(h.stack)-1]
+			if parent := h.top(); parent.rings() && f.index == 0 {
+				parent.exteriorDropped = true
+			}
+			return nil
+		}
+	}
+	if !f.emitted && (f.children > 0 || f.dropped) {
+		// every member collapsed or was clipped out
+		h.stack = h.stack[:len(h.stack)-1]
+		return nil
+	}
+	if err := h.emit(); err != nil {
+		return err
+	}
+	h.stack = h.stack[:len(h.stack)-1]
+	return h.next.EndPart()
+}
+
+func (h *partFilter) EndGeometry() error {
+	f := h.top()
+	if f.leaf() {
+		// a collapsed linestring is left EMPTY
+		if _, err := h.flush(f); err != nil {
+			return err
+		}
+	}
+	h.stack = h.stack[:len(h.stack)-1]
+	return h.next.EndGeometry()
+}
@@ -0,0 +1,92 @@
+package wkttoorb
+
+import (
+	"strings"
+)
+
+// Header describes a geometry without its coordinates
+type Header struct {
+	Type GeometryType
+	// Dims is given by the dimension keyword
+	// or by the number of values of the first coordinate
+	Dims Dims
+	// SRID is given by the EWKT prefix, 0 if there is none
+	SRID  int
+	Empty bool
+}
+
+// Peek returns the header of the WKT or EWKT geometry s
+// only the leading tokens are read, up to the first coordinate at most
+// so the rest of s is not validated
+func Peek(s string) (Header, error) {
+	p := NewParser(strings.NewReader(s))
+	return p.peekHeader()
+}
+
+func (p *Parser) peekHeader() (h Header, err error) {
+	if err := p.parseSRID(); err != nil {
+		return h, err
+	}
+	h.SRID = p.srid
+
+	t, err := p.scanToken()
+	if err != nil {
+		return h, err
+	}
+	gtype, ok := geometryTypes[t.ttype]
+	if !ok {
+		return h, p.unexpected(t, "geometry type")
+	}
+	h.Type = gtype
+
+	t, err = p.scanToken()
+	if err != nil {
+		return h, err
+	}
+	if d, ok := dimensions[t.ttype]; ok {
+		h.Dims = d
+		t, err = p.scanToken()
+		if err != nil {
+			return h, err
+		}
+		h.Empty = t.ttype == Empty
+		return h, nil
+	}
+	switch t.ttype {
+	case Empty:
+		h.Empty = true
+		return h, nil
+	case LeftParen:
+	default:
+		return h, p.unexpected(t, "'('", "empty")
+	}
+
+	// no keyword, look for the first coordinate or the keyword of a collection member
+	for {
+		t, err = p.scanToken()
+		if err != nil {
+			return h, err
+		}
+		if d, ok := dimensions[t.ttype]; ok {
+			h.Dims = d
+			return h, nil
+		}
+		switch t.ttype {
+		case Float:
+			n := 1
+			for ; n < 4; n++ {
+				t, err = p.scanToken()
+				if err != nil {
+					return h, err
+				}
+				if t.ttype != Float {
+					break
+				}
+			}
+			h.Dims = inferDims(n)
+			return h, nil
+		case Eof:
+			return h, nil
+		}
+	}
+}
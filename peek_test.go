@@ -0,0 +1,37 @@
+package wkttoorb
+
+import (
+	"testing"
+)
+
+func Test_peek(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"POINT EMPTY",
+		"SRID=4326;LINESTRING Z (1 2 3, 4 5 6)",
+		"srid = 3857 ; POLYGON M EMPTY",
+		"MULTIPOLYGON (((1 2 3 4, 5 6 7 8",
+		"GEOMETRYCOLLECTION (POINT EMPTY, POINT M (1 2 3))",
+		"MULTIPOINT (EMPTY, (1 2 3))",
+	}
+	outputs := []Header{
+		{Type: TypePoint, Dims: XY},
+		{Type: TypePoint, Dims: XY, Empty: true},
+		{Type: TypeLineString, Dims: XYZ, SRID: 4326},
+		{Type: TypePolygon, Dims: XYM, SRID: 3857, Empty: true},
+		{Type: TypeMultiPolygon, Dims: XYZM},
+		{Type: TypeGeometryCollection, Dims: XYM},
+		{Type: TypeMultiPoint, Dims: XYZ},
+	}
+
+	for i, str := range inputs {
+		h, err := Peek(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if h != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %+v", i, h)
+		}
+	}
+}
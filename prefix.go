@@ -0,0 +1,44 @@
+package wkttoorb
+
+import (
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// ParsePrefix parses the WKT or EWKT geometry at the start of s
+// unlike Scan it stops after the geometry and returns the number of bytes consumed,
+// leading white space included, leaving the rest of s to the caller
+func ParsePrefix(s string, opts ...Option) (orb.Geometry, int, error) {
+	var b OrbBuilder
+	p := NewParser(strings.NewReader(s), opts...)
+	n, err := p.ParsePrefixWith(&b)
+	if err != nil {
+		if _, ok := err.(ErrorList); ok {
+			return b.Geometry(), n, err
+		}
+		return nil, 0, err
+	}
+	return b.Geometry(), n, nil
+}
+
+// ParsePrefixWith parses a geometry sending the parsing events to h
+// without expecting the end of the input after it
+// it returns the offset of the end of the geometry in the input
+// in recovery mode the syntax errors are returned as an ErrorList along with the offset
+func (p *Parser) ParsePrefixWith(h Handler) (int, error) {
+	if err := p.reset(h); err != nil {
+		return 0, err
+	}
+	err := p.parseSRID()
+	if err == nil {
+		err = p.parseGeometry()
+	}
+	if err = p.recovered(err); err != nil {
+		if _, ok := err.(ErrorList); ok {
+			return p.end, err
+		}
+		return 0, err
+	}
+	return p.end, nil
+}
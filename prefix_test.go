@@ -0,0 +1,69 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_parsePrefix(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"POINT(1 2), 10, meters)",
+		"  LINESTRING (1 2, 3 4) AND x",
+		"POLYGON EMPTY)",
+		"SRID=4326;POINT Z (1 2 3);rest",
+		"\u00a0POINT (1 2)\u00a0x",
+	}
+	outputs := []orb.Geometry{
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+		orb.LineString{{1, 2}, {3, 4}},
+		orb.Polygon{},
+		orb.Point{1, 2},
+		orb.Point{1, 2},
+	}
+	rests := []string{
+		"",
+		", 10, meters)",
+		" AND x",
+		")",
+		";rest",
+		"\u00a0x",
+	}
+
+	for i, str := range inputs {
+		geo, n, err := ParsePrefix(str)
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+		}
+		if str[n:] != rests[i] {
+			t.Errorf("incorrect length returned on test %d: %q", i, str[n:])
+		}
+	}
+
+	if _, _, err := ParsePrefix("POINT (1 2"); err == nil {
+		t.Error("expected error on unclosed point")
+	}
+}
+
+func Test_parsePrefixRecovery(t *testing.T) {
+	str := "LINESTRING (1 2, 3 x, 5 6) rest"
+	geo, n, err := ParsePrefix(str, WithRecovery())
+
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) != 1 || errs[0].Pos != 19 {
+		t.Errorf("incorrect errors returned %v", err)
+	}
+	if !reflect.DeepEqual(geo, orb.LineString{{1, 2}, {5, 6}}) {
+		t.Errorf("incorrect value returned %v", geo)
+	}
+	if str[n:] != " rest" {
+		t.Errorf("incorrect length returned %q", str[n:])
+	}
+}
@@ -0,0 +1,64 @@
+package wkttoorb
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/simplify"
+)
+
+// simplifier is implemented by the orb/simplify simplifiers
+type simplifier interface {
+	LineString(orb.LineString) orb.LineString
+	Ring(orb.Ring) orb.Ring
+}
+
+// simplifyWindow is the number of coordinates of a line or ring buffered before they are simplified
+const simplifyWindow = 256
+
+// WithDouglasPeucker simplifies every line and ring with the Douglas-Peucker algorithm
+// as it is parsed, the coordinates are simplified by windows of simplifyWindow as they
+// are read so memory is bounded by the simplified output, the windows share their end points
+// so the result may differ slightly from simplifying the whole line at once
+// the lines and rings collapsing below 2 and 4 points are dropped
+func WithDouglasPeucker(tolerance float64) Option {
+	return withSimplifier(simplify.DouglasPeucker(tolerance))
+}
+
+// WithVisvalingam simplifies every line and ring with the Visvalingam algorithm
+// removing the points forming triangles of an area below tolerance,
+// as for WithDouglasPeucker the collapsed lines and rings are dropped
+func WithVisvalingam(tolerance float64) Option {
+	return withSimplifier(simplify.VisvalingamThreshold(tolerance))
+}
+
+func withSimplifier(s simplifier) Option {
+	return func(p *Parser) {
+		// ls is reused for every window of the parser
+		var ls orb.LineString
+		p.simplify = func(coords []coord, ring bool) []coord {
+			ls = ls[:0]
+			for _, c := range coords {
+				ls = append(ls, c.point)
+			}
+			// the simplifiers work in place, moving the points kept to the start of ls
+			var simplified orb.LineString
+			if ring {
+				simplified = orb.LineString(s.Ring(orb.Ring(ls)))
+			} else {
+				simplified = s.LineString(ls)
+			}
+			return keep(coords, simplified)
+		}
+	}
+}
+
+// keep returns the coordinates of coords matching the points of ls
+// which is a subsequence of them, keeping their Z and M values
+func keep(coords []coord, ls orb.LineString) []coord {
+	out := coords[:0]
+	for _, c := range coords {
+		if len(out) < len(ls) && c.point == ls[len(out)] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
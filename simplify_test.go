@@ -0,0 +1,93 @@
+package wkttoorb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_douglasPeucker(t *testing.T) {
+	inputs := []string{
+		"LINESTRING (0 0, 1 0.1, 2 -0.1, 3 5, 4 6, 5 7, 6 8.1, 7 9)",
+		"POLYGON ((0 0, 5 0.1, 10 0, 10 10, 0 10, 0 0), (1 1, 1.1 1, 1.1 1.1, 1 1))",
+		"MULTILINESTRING ((0 0, 1 0.01, 2 0), (0 0, 0 5))",
+		"POINT (1 2)",
+	}
+	outputs := []orb.Geometry{
+		orb.LineString{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}},
+		orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		orb.MultiLineString{{{0, 0}, {2, 0}}, {{0, 0}, {0, 5}}},
+		orb.Point{1, 2},
+	}
+
+	for i, str := range inputs {
+		g, err := Scan(str, WithDouglasPeucker(0.5))
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(g, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, g)
+		}
+	}
+}
+
+func Test_visvalingam(t *testing.T) {
+	g, err := Scan("LINESTRING (0 0, 1 0.1, 2 0, 3 3, 4 0)", WithVisvalingam(0.5))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := orb.LineString{{0, 0}, {2, 0}, {3, 3}, {4, 0}}
+	if !reflect.DeepEqual(g, expected) {
+		t.Errorf("incorrect value returned %#v", g)
+	}
+}
+
+func Test_simplifyZM(t *testing.T) {
+	trace := &traceHandler{}
+	p := NewParser(strings.NewReader("LINESTRING ZM (0 0 1 2, 1 0.1 3 4, 2 0 5 6)"), WithDouglasPeucker(1))
+	if err := p.ParseWith(trace); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	expected := []string{"begin LineString 3", "0 0 1 2", "2 0 5 6", "end"}
+	if !reflect.DeepEqual(trace.events, expected) {
+		t.Errorf("incorrect events %v", trace.events)
+	}
+}
+
+func Test_simplifyLargeRing(t *testing.T) {
+	n := 100000
+	var b strings.Builder
+	b.WriteString("POLYGON ((")
+	for i := 0; i <= n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		a := 2 * math.Pi * float64(i%n) / float64(n)
+		fmt.Fprintf(&b, "%f %f", 1000*math.Cos(a), 1000*math.Sin(a))
+	}
+	b.WriteString("))")
+	str := b.String()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	g, err := Scan(str, WithDouglasPeucker(1))
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	ring := g.(orb.Polygon)[0]
+	if len(ring) < 4 || len(ring) > n/100 || ring[0] != ring[len(ring)-1] {
+		t.Errorf("incorrect ring returned with %d points", len(ring))
+	}
+	// buffering the whole ring would take 32 bytes per vertex
+	if perVertex := float64(after.TotalAlloc-before.TotalAlloc) / float64(n); perVertex > 8 {
+		t.Errorf("%.1f bytes allocated per vertex", perVertex)
+	}
+}
@@ -0,0 +1,66 @@
+package wkttoorb
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Transform is applied to every coordinate as it is parsed
+type Transform func(orb.Point) orb.Point
+
+// TransformZM is the Z/M aware variant of Transform
+// z and m are NaN when the parsed geometry does not carry them
+type TransformZM func(point orb.Point, z, m float64) (orb.Point, float64, float64)
+
+var (
+	// MercatorToWGS84 converts Web Mercator coordinates to WGS84 lon/lat
+	MercatorToWGS84 = Transform(project.Mercator.ToWGS84)
+	// WGS84ToMercator converts WGS84 lon/lat coordinates to Web Mercator
+	WGS84ToMercator = Transform(project.WGS84.ToMercator)
+)
+
+// SwapXY swaps the axis of a point, turning lat/lon into lon/lat and back
+func SwapXY(point orb.Point) orb.Point {
+	return orb.Point{point[1], point[0]}
+}
+
+// WithTransform applies t to every parsed coordinate
+// transforms given through several options are applied in order
+func WithTransform(t Transform) Option {
+	return WithTransformZM(func(point orb.Point, z, m float64) (orb.Point, float64, float64) {
+		return t(point), z, m
+	})
+}
+
+// WithTransformZM applies t to every parsed coordinate along with its Z and M values
+func WithTransformZM(t TransformZM) Option {
+	return func(p *Parser) {
+		prev := p.transform
+		if prev == nil {
+			p.transform = t
+			return
+		}
+		p.transform = func(point orb.Point, z, m float64) (orb.Point, float64, float64) {
+			return t(prev(point, z, m))
+		}
+	}
+}
+
+// AxisOrder is the order of the values of the coordinates in the input
+type AxisOrder int
+
+const (
+	// LonLat is the X, Y order used by orb
+	LonLat AxisOrder = iota
+	// LatLon is the order of geographic SRIDs such as 4326 in MySQL 8 WKT
+	LatLon
+)
+
+// WithAxisOrder sets the axis order of the input, LatLon coordinates are swapped
+// so that the parsed geometries are lon/lat
+func WithAxisOrder(order AxisOrder) Option {
+	if order == LatLon {
+		return WithTransform(SwapXY)
+	}
+	return func(p *Parser) {}
+}
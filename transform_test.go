@@ -0,0 +1,85 @@
+package wkttoorb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_transform(t *testing.T) {
+	inputs := []string{
+		"POINT (10 20)",
+		"LINESTRING (1 2, 3 4)",
+		"POLYGON ((1 2, 3 4, 5 6, 1 2))",
+	}
+	outputs := []orb.Geometry{
+		orb.Point{20, 10},
+		orb.LineString{{2, 1}, {4, 3}},
+		orb.Polygon{{{2, 1}, {4, 3}, {6, 5}, {2, 1}}},
+	}
+
+	for i, str := range inputs {
+		geo, err := Scan(str, WithTransform(SwapXY))
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(geo, outputs[i]) {
+			t.Errorf("incorrect value returned on test %d", i)
+		}
+	}
+}
+
+func Test_transformZM(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"POINT Z (1 2 3)",
+		"POINT M (1 2 4)",
+		"POINT ZM (1 2 3 4)",
+	}
+	outputs := [][2]float64{
+		{math.NaN(), math.NaN()},
+		{3, math.NaN()},
+		{math.NaN(), 4},
+		{3, 4},
+	}
+
+	for i, str := range inputs {
+		var z, m float64
+		_, err := Scan(str, WithTransformZM(func(p orb.Point, pz, pm float64) (orb.Point, float64, float64) {
+			z, m = pz, pm
+			return p, pz, pm
+		}))
+
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !sameFloat(z, outputs[i][0]) || !sameFloat(m, outputs[i][1]) {
+			t.Errorf("incorrect z/m on test %d: %v %v", i, z, m)
+		}
+	}
+}
+
+func Test_transformChain(t *testing.T) {
+	geo, err := Scan("POINT (0 0)", WithTransform(WGS84ToMercator), WithTransform(MercatorToWGS84), WithTransform(SwapXY))
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(geo, orb.Point{0, 0}) {
+		t.Errorf("incorrect value returned %v", geo)
+	}
+
+	geo, err = Scan("POINT (180 0)", WithTransform(WGS84ToMercator))
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if p := geo.(orb.Point); math.Abs(p[0]-20037508.342789244) > 1e-6 || math.Abs(p[1]) > 1e-6 {
+		t.Errorf("incorrect value returned %v", geo)
+	}
+}
+
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
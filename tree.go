@@ -0,0 +1,190 @@
+package wkttoorb
+
+import (
+	"strings"
+)
+
+// NodeKind is the kind of a syntax tree node
+type NodeKind int
+
+const (
+	// GeometryNode is a geometry from its keyword to its closing paren or EMPTY
+	GeometryNode NodeKind = iota
+	// PartNode is a ring, a line or a polygon of a multi geometry,
+	// or a point of a multipoint written between parens or EMPTY,
+	// from its opening paren to its closing paren or EMPTY
+	PartNode
+	// CoordinateNode is a coordinate from its first value to its last one
+	CoordinateNode
+)
+
+// Span is the byte range [Start, End) of a node in the source
+type Span struct {
+	Start, End int
+}
+
+// Node is a node of the syntax tree of a WKT string
+type Node struct {
+	Kind NodeKind
+	Span Span
+	// Text is the source text of the node
+	Text string
+
+	// Type, Keyword and DimsKeyword are only set on geometry nodes
+	// the keywords are spelled as in the source, DimsKeyword is empty
+	// if the geometry has no Z, M or ZM keyword
+	Type        GeometryType
+	Keyword     string
+	DimsKeyword string
+
+	// Values are only set on coordinate nodes
+	Values []Value
+
+	Children []*Node
+}
+
+// Value is a number of a coordinate
+type Value struct {
+	Span Span
+	// Text is the lexeme of the number as written in the source
+	Text  string
+	Value float64
+}
+
+// Tree is the syntax tree of a WKT or EWKT string
+type Tree struct {
+	Source string
+	// SRID is given by the EWKT prefix, 0 if there is none
+	SRID int
+	Root *Node
+}
+
+// ParseTree parses the WKT or EWKT string s into a syntax tree
+// keeping the position of each geometry, part and coordinate in s
+func ParseTree(s string) (*Tree, error) {
+	b := treeBuilder{src: s}
+	p := NewParser(strings.NewReader(s))
+	p.tracer = &b
+	if err := p.ParseWith(nopHandler{}); err != nil {
+		return nil, err
+	}
+	return &Tree{Source: s, SRID: p.SRID(), Root: b.root}, nil
+}
+
+// Coordinates returns the coordinate nodes in source order
+// so the i-th vertex of the geometry is the i-th node
+func (t *Tree) Coordinates() []*Node {
+	var coords []*Node
+	t.Walk(func(n *Node) {
+		if n.Kind == CoordinateNode {
+			coords = append(coords, n)
+		}
+	})
+	return coords
+}
+
+// Walk calls f on every node of the tree in source order, parents before children
+func (t *Tree) Walk(f func(n *Node)) {
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		f(n)
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(t.Root)
+}
+
+// Replace returns the source with the text of the span replaced by text
+// leaving the rest of the source untouched
+func (t *Tree) Replace(span Span, text string) string {
+	return t.Source[:span.Start] + text + t.Source[span.End:]
+}
+
+// tracer receives the source positions of the syntax elements as they are parsed
+// unlike the handler events they are never delayed by the dimension inference
+type tracer interface {
+	beginGeometry(keyword Token, dimsKeyword *Token)
+	endGeometry(end int)
+	beginPart(start int)
+	endPart(end int)
+	coordinate(toks []Token, values []float64)
+}
+
+// treeBuilder is a tracer building the syntax tree of src
+type treeBuilder struct {
+	src   string
+	stack []*Node
+	root  *Node
+}
+
+func (b *treeBuilder) push(n *Node) {
+	b.stack = append(b.stack, n)
+}
+
+// pop closes the node on top of the stack at end and adds it to its parent
+func (b *treeBuilder) pop(end int) {
+	n := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	n.Span.End = end
+	n.Text = b.src[n.Span.Start:end]
+	b.add(n)
+}
+
+func (b *treeBuilder) add(n *Node) {
+	if len(b.stack) == 0 {
+		b.root = n
+		return
+	}
+	parent := b.stack[len(b.stack)-1]
+	parent.Children = append(parent.Children, n)
+}
+
+func (b *treeBuilder) text(t Token) string {
+	return b.src[t.pos : t.pos+t.size()]
+}
+
+func (b *treeBuilder) beginGeometry(keyword Token, dimsKeyword *Token) {
+	n := &Node{
+		Kind:    GeometryNode,
+		Span:    Span{Start: keyword.pos},
+		Type:    geometryTypes[keyword.ttype],
+		Keyword: b.text(keyword),
+	}
+	if dimsKeyword != nil {
+		n.DimsKeyword = b.text(*dimsKeyword)
+	}
+	b.push(n)
+}
+
+func (b *treeBuilder) endGeometry(end int) {
+	b.pop(end)
+}
+
+func (b *treeBuilder) beginPart(start int) {
+	b.push(&Node{Kind: PartNode, Span: Span{Start: start}})
+}
+
+func (b *treeBuilder) endPart(end int) {
+	b.pop(end)
+}
+
+func (b *treeBuilder) coordinate(toks []Token, values []float64) {
+	n := &Node{Kind: CoordinateNode}
+	for i, t := range toks {
+		span := Span{t.pos, t.pos + t.size()}
+		n.Values = append(n.Values, Value{span, b.text(t), values[i]})
+	}
+	n.Span = Span{n.Values[0].Span.Start, n.Values[len(n.Values)-1].Span.End}
+	n.Text = b.src[n.Span.Start:n.Span.End]
+	b.add(n)
+}
+
+// nopHandler ignores the parsing events
+type nopHandler struct{}
+
+func (nopHandler) BeginGeometry(GeometryType, Dims) error { return nil }
+func (nopHandler) BeginPart() error                       { return nil }
+func (nopHandler) Coordinate(x, y, z, m float64) error    { return nil }
+func (nopHandler) EndPart() error                         { return nil }
+func (nopHandler) EndGeometry() error                     { return nil }
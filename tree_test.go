@@ -0,0 +1,107 @@
+package wkttoorb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseTree(t *testing.T) {
+	s := "SRID=4326;Polygon z ((0 0 1, 1 0 1, 0 1 1, 0 0 1), EMPTY)"
+	tree, err := ParseTree(s)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if tree.SRID != 4326 {
+		t.Errorf("incorrect srid %d", tree.SRID)
+	}
+
+	root := tree.Root
+	if root.Kind != GeometryNode || root.Type != TypePolygon || root.Keyword != "Polygon" || root.DimsKeyword != "z" {
+		t.Errorf("incorrect root node %+v", root)
+	}
+	if root.Text != s[10:] {
+		t.Errorf("incorrect root text %q", root.Text)
+	}
+	if len(root.Children) != 2 {
+		t.Fatalf("incorrect number of parts %d", len(root.Children))
+	}
+	if text := root.Children[0].Text; text != "(0 0 1, 1 0 1, 0 1 1, 0 0 1)" {
+		t.Errorf("incorrect part text %q", text)
+	}
+	if text := root.Children[1].Text; text != "EMPTY" {
+		t.Errorf("incorrect empty part text %q", text)
+	}
+
+	coords := tree.Coordinates()
+	if len(coords) != 4 {
+		t.Fatalf("incorrect number of coordinates %d", len(coords))
+	}
+	second := coords[1]
+	if second.Text != "1 0 1" || second.Span != (Span{29, 34}) {
+		t.Errorf("incorrect coordinate node %+v", second)
+	}
+	values := []Value{{Span{29, 30}, "1", 1}, {Span{31, 32}, "0", 0}, {Span{33, 34}, "1", 1}}
+	if !reflect.DeepEqual(second.Values, values) {
+		t.Errorf("incorrect coordinate values %v", second.Values)
+	}
+}
+
+func Test_parseTreeCollection(t *testing.T) {
+	s := "GEOMETRYCOLLECTION (POINT (1 2), MULTIPOINT ((3 4), 5 6))"
+	tree, err := ParseTree(s)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var texts []string
+	tree.Walk(func(n *Node) {
+		texts = append(texts, n.Text)
+	})
+	expected := []string{s, "POINT (1 2)", "1 2", "MULTIPOINT ((3 4), 5 6)", "(3 4)", "3 4", "5 6"}
+	if !reflect.DeepEqual(texts, expected) {
+		t.Errorf("incorrect nodes %q", texts)
+	}
+}
+
+func Test_parseTreeMultiPoint(t *testing.T) {
+	tree, err := ParseTree("MULTIPOINT ((1 2), EMPTY, 3 4)")
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var kinds []NodeKind
+	var texts []string
+	for _, n := range tree.Root.Children {
+		kinds = append(kinds, n.Kind)
+		texts = append(texts, n.Text)
+	}
+	if !reflect.DeepEqual(kinds, []NodeKind{PartNode, PartNode, CoordinateNode}) {
+		t.Errorf("incorrect member kinds %v", kinds)
+	}
+	if !reflect.DeepEqual(texts, []string{"(1 2)", "EMPTY", "3 4"}) {
+		t.Errorf("incorrect member texts %q", texts)
+	}
+	if n := tree.Root.Children[0]; len(n.Children) != 1 || n.Children[0].Text != "1 2" {
+		t.Errorf("incorrect point part %+v", n)
+	}
+}
+
+func Test_treeReplace(t *testing.T) {
+	s := "LINESTRING (1  2, 3 4, 5.50 6)"
+	tree, err := ParseTree(s)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	out := tree.Replace(tree.Coordinates()[1].Span, "30 40")
+	if out != "LINESTRING (1  2, 30 40, 5.50 6)" {
+		t.Errorf("incorrect replaced string %q", out)
+	}
+}
+
+func Test_parseTreeInvalid(t *testing.T) {
+	_, err := ParseTree("POINT (1 2")
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
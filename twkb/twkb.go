@@ -0,0 +1,470 @@
+// Package twkb encodes and decodes orb geometries in the Tiny Well-known Binary format
+// https://github.com/TWKB/Specification
+//
+// The coordinates are rounded to the precision given when encoding and stored
+// as varint deltas, only the X and Y values are written, the Z and M values of
+// decoded geometries are skipped.
+package twkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/Succo/wkttoorb"
+	"github.com/paulmach/orb"
+	"github.com/pkg/errors"
+)
+
+// geometry type codes of the header
+const (
+	pointType = iota + 1
+	lineStringType
+	polygonType
+	multiPointType
+	multiLineStringType
+	multiPolygonType
+	collectionType
+)
+
+// metadata header flags
+const (
+	bboxFlag = 1 << iota
+	sizeFlag
+	idListFlag
+	extendedDimsFlag
+	emptyFlag
+)
+
+// Option configures the encoding of a geometry
+type Option func(*encoder)
+
+// Precision sets the number of decimal digits kept, negative values round
+// to tens, hundreds and so on, it must be between -8 and 7 and defaults to 0
+func Precision(p int) Option {
+	return func(e *encoder) {
+		e.precision = p
+	}
+}
+
+// WithBBox writes the bounding box of the geometry in its header
+func WithBBox() Option {
+	return func(e *encoder) {
+		e.bbox = true
+	}
+}
+
+// WithSize writes the size of the geometry in its header
+// so that readers can skip it without decoding it
+func WithSize() Option {
+	return func(e *encoder) {
+		e.size = true
+	}
+}
+
+// WithIDs writes an id for each member of a multi geometry or collection
+// Marshal returns an error if ids are given for a point, a linestring or a polygon
+func WithIDs(ids []int64) Option {
+	return func(e *encoder) {
+		e.ids = ids
+	}
+}
+
+// FromWKT parses the WKT string s and encodes it in TWKB
+func FromWKT(s string, opts ...Option) ([]byte, error) {
+	g, err := wkttoorb.Scan(s)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(g, opts...)
+}
+
+// Marshal encodes g in TWKB
+func Marshal(g orb.Geometry, opts ...Option) ([]byte, error) {
+	var e encoder
+	for _, opt := range opts {
+		opt(&e)
+	}
+	if e.precision < -8 || e.precision > 7 {
+		return nil, fmt.Errorf("precision %d out of range", e.precision)
+	}
+	e.scale = math.Pow10(e.precision)
+	if err := e.geometry(g, e.ids); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+type encoder struct {
+	precision int
+	bbox      bool
+	size      bool
+	ids       []int64
+
+	scale float64
+	buf   bytes.Buffer
+}
+
+// body writes the content of a geometry after its header
+type body struct {
+	buf  bytes.Buffer
+	last [2]int64
+}
+
+func (b *body) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	b.buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+func (b *body) varint(v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	b.buf.Write(tmp[:binary.PutVarint(tmp[:], v)])
+}
+
+func (e *encoder) point(b *body, p orb.Point) {
+	for i := range p {
+		v := int64(math.Round(p[i] * e.scale))
+		b.varint(v - b.last[i])
+		b.last[i] = v
+	}
+}
+
+func (e *encoder) points(b *body, ps []orb.Point) {
+	b.uvarint(uint64(len(ps)))
+	for _, p := range ps {
+		e.point(b, p)
+	}
+}
+
+func (e *encoder) polygon(b *body, p orb.Polygon) {
+	b.uvarint(uint64(len(p)))
+	for _, r := range p {
+		e.points(b, r)
+	}
+}
+
+// geometry writes g with its header, ids are written for its members if not nil
+func (e *encoder) geometry(g orb.Geometry, ids []int64) error {
+	var b body
+	gtype, n := 0, 0
+	hasIDs := false
+	switch g := g.(type) {
+	case orb.Point:
+		gtype, n = pointType, 1
+		e.point(&b, g)
+	case orb.LineString:
+		gtype, n = lineStringType, len(g)
+		e.points(&b, g)
+	case orb.Polygon:
+		gtype, n = polygonType, len(g)
+		e.polygon(&b, g)
+	case orb.MultiPoint:
+		gtype, n = multiPointType, len(g)
+		b.uvarint(uint64(n))
+		hasIDs = writeIDs(&b, ids, n)
+		for _, p := range g {
+			e.point(&b, p)
+		}
+	case orb.MultiLineString:
+		gtype, n = multiLineStringType, len(g)
+		b.uvarint(uint64(n))
+		hasIDs = writeIDs(&b, ids, n)
+		for _, ls := range g {
+			e.points(&b, ls)
+		}
+	case orb.MultiPolygon:
+		gtype, n = multiPolygonType, len(g)
+		b.uvarint(uint64(n))
+		hasIDs = writeIDs(&b, ids, n)
+		for _, p := range g {
+			e.polygon(&b, p)
+		}
+	case orb.Collection:
+		gtype, n = collectionType, len(g)
+		b.uvarint(uint64(n))
+		hasIDs = writeIDs(&b, ids, n)
+		for _, m := range g {
+			var member encoder
+			member.precision, member.scale, member.bbox, member.size = e.precision, e.scale, e.bbox, e.size
+			if err := member.geometry(m, nil); err != nil {
+				return err
+			}
+			b.buf.Write(member.buf.Bytes())
+		}
+	default:
+		return fmt.Errorf("unsupported geometry type %T", g)
+	}
+	if ids != nil && gtype <= polygonType {
+		return fmt.Errorf("ids given for a %s, only multi geometries and collections have ids", g.GeoJSONType())
+	}
+	if ids != nil && len(ids) != n {
+		return fmt.Errorf("%d ids given for %d members", len(ids), n)
+	}
+
+	var meta byte
+	var header body
+	if n == 0 {
+		meta |= emptyFlag
+		b.buf.Reset()
+	} else if e.bbox {
+		meta |= bboxFlag
+		bound := g.Bound()
+		for i := range bound.Min {
+			min := int64(math.Round(bound.Min[i] * e.scale))
+			max := int64(math.Round(bound.Max[i] * e.scale))
+			header.varint(min)
+			header.varint(max - min)
+		}
+	}
+	if hasIDs {
+		meta |= idListFlag
+	}
+	header.buf.Write(b.buf.Bytes())
+
+	e.buf.WriteByte(byte(gtype) | byte(zigzag(e.precision))<<4)
+	if e.size {
+		meta |= sizeFlag
+	}
+	e.buf.WriteByte(meta)
+	if e.size {
+		var size body
+		size.uvarint(uint64(header.buf.Len()))
+		e.buf.Write(size.buf.Bytes())
+	}
+	e.buf.Write(header.buf.Bytes())
+	return nil
+}
+
+// writeIDs writes the ids of the n members of a geometry, it returns false if there are none
+func writeIDs(b *body, ids []int64, n int) bool {
+	if ids == nil || n == 0 {
+		return false
+	}
+	for _, id := range ids {
+		b.varint(id)
+	}
+	return true
+}
+
+func zigzag(v int) int {
+	return (v << 1) ^ (v >> 63)
+}
+
+func unzigzag(v int) int {
+	return int(uint(v)>>1) ^ -(v & 1)
+}
+
+// Unmarshal decodes the TWKB geometry data
+func Unmarshal(data []byte) (orb.Geometry, error) {
+	g, _, err := UnmarshalIDs(data)
+	return g, err
+}
+
+// UnmarshalIDs decodes the TWKB geometry data along with the ids of its members
+// the ids are nil if data does not have an id list
+func UnmarshalIDs(data []byte) (orb.Geometry, []int64, error) {
+	d := decoder{r: bytes.NewReader(data)}
+	g, ids, err := d.geometry()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid twkb")
+	}
+	if d.r.Len() > 0 {
+		return nil, nil, fmt.Errorf("invalid twkb, %d bytes after the geometry", d.r.Len())
+	}
+	return g, ids, nil
+}
+
+type decoder struct {
+	r *bytes.Reader
+
+	scale float64
+	// dims is the number of values of each coordinate, the first two are kept
+	dims int
+	last [4]int64
+}
+
+func (d *decoder) uvarint() (int, error) {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, err
+	}
+	// every element takes at least a byte
+	if v > uint64(d.r.Len()) {
+		return 0, fmt.Errorf("count %d larger than the input", v)
+	}
+	return int(v), nil
+}
+
+func (d *decoder) point() (orb.Point, error) {
+	var p orb.Point
+	for i := 0; i < d.dims; i++ {
+		v, err := binary.ReadVarint(d.r)
+		if err != nil {
+			return p, err
+		}
+		d.last[i] += v
+		if i < 2 {
+			p[i] = float64(d.last[i]) / d.scale
+		}
+	}
+	return p, nil
+}
+
+func (d *decoder) points() ([]orb.Point, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]orb.Point, n)
+	for i := range ps {
+		if ps[i], err = d.point(); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+func (d *decoder) polygon() (orb.Polygon, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	p := make(orb.Polygon, n)
+	for i := range p {
+		ps, err := d.points()
+		if err != nil {
+			return nil, err
+		}
+		p[i] = orb.Ring(ps)
+	}
+	return p, nil
+}
+
+// header reads the header of a geometry, it returns its type and metadata flags
+func (d *decoder) header() (int, byte, error) {
+	typ, err := d.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	meta, err := d.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	d.scale = math.Pow10(unzigzag(int(typ >> 4)))
+	d.dims = 2
+	d.last = [4]int64{}
+
+	if meta&extendedDimsFlag != 0 {
+		ext, err := d.r.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		d.dims += int(ext & 1)
+		d.dims += int(ext >> 1 & 1)
+	}
+	if meta&sizeFlag != 0 {
+		if _, err := d.uvarint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	if meta&bboxFlag != 0 {
+		for i := 0; i < 2*d.dims; i++ {
+			if _, err := binary.ReadVarint(d.r); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return int(typ & 0x0f), meta, nil
+}
+
+func (d *decoder) geometry() (orb.Geometry, []int64, error) {
+	gtype, meta, err := d.header()
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta&emptyFlag != 0 {
+		switch gtype {
+		case pointType:
+			return orb.Point{}, nil, nil
+		case lineStringType:
+			return orb.LineString{}, nil, nil
+		case polygonType:
+			return orb.Polygon{}, nil, nil
+		case multiPointType:
+			return orb.MultiPoint{}, nil, nil
+		case multiLineStringType:
+			return orb.MultiLineString{}, nil, nil
+		case multiPolygonType:
+			return orb.MultiPolygon{}, nil, nil
+		case collectionType:
+			return orb.Collection{}, nil, nil
+		}
+		return nil, nil, fmt.Errorf("unknown geometry type %d", gtype)
+	}
+
+	switch gtype {
+	case pointType:
+		p, err := d.point()
+		return p, nil, err
+	case lineStringType:
+		ps, err := d.points()
+		return orb.LineString(ps), nil, err
+	case polygonType:
+		p, err := d.polygon()
+		return p, nil, err
+	}
+
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, nil, err
+	}
+	var ids []int64
+	if meta&idListFlag != 0 {
+		ids = make([]int64, n)
+		for i := range ids {
+			if ids[i], err = binary.ReadVarint(d.r); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	switch gtype {
+	case multiPointType:
+		mp := make(orb.MultiPoint, n)
+		for i := range mp {
+			if mp[i], err = d.point(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return mp, ids, nil
+	case multiLineStringType:
+		mls := make(orb.MultiLineString, n)
+		for i := range mls {
+			ps, err := d.points()
+			if err != nil {
+				return nil, nil, err
+			}
+			mls[i] = orb.LineString(ps)
+		}
+		return mls, ids, nil
+	case multiPolygonType:
+		mp := make(orb.MultiPolygon, n)
+		for i := range mp {
+			if mp[i], err = d.polygon(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return mp, ids, nil
+	case collectionType:
+		c := make(orb.Collection, n)
+		for i := range c {
+			if c[i], _, err = d.geometry(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return c, ids, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown geometry type %d", gtype)
+	}
+}
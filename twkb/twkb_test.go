@@ -0,0 +1,134 @@
+package twkb
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func Test_marshal(t *testing.T) {
+	inputs := []string{
+		"POINT (1 2)",
+		"LINESTRING (1 1, 5 5)",
+		"POINT (1.5 2.5)",
+		"LINESTRING (1 1, 5 5)",
+		"LINESTRING (1 1, 5 5)",
+		"POLYGON EMPTY",
+		"MULTIPOINT (1 2, 3 4)",
+	}
+	options := [][]Option{
+		nil,
+		nil,
+		{Precision(1)},
+		{WithBBox()},
+		{WithSize()},
+		nil,
+		{WithIDs([]int64{7, -1})},
+	}
+	outputs := []string{
+		"01000204",
+		"02000202020808",
+		"21001e32",
+		"0201020802080202020808",
+		"0202050202020808",
+		"0310",
+		"0404020e0102040404",
+	}
+
+	for i, str := range inputs {
+		b, err := FromWKT(str, options[i]...)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if out := hex.EncodeToString(b); out != outputs[i] {
+			t.Errorf("incorrect value returned on test %d: %s", i, out)
+		}
+	}
+}
+
+func Test_roundTrip(t *testing.T) {
+	inputs := []orb.Geometry{
+		orb.Point{-1.25, 2.5},
+		orb.LineString{{1, 2}, {3.75, -4}},
+		orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		orb.MultiPoint{{1, 2}, {3, 4}},
+		orb.MultiLineString{{{1, 2}, {3, 4}}, {}},
+		orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}},
+		orb.Collection{orb.Point{1, 2}, orb.LineString{{3, 4}, {5, 6}}, orb.MultiPoint{}},
+		orb.LineString{},
+	}
+
+	for i, g := range inputs {
+		b, err := Marshal(g, Precision(2), WithBBox(), WithSize())
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+			continue
+		}
+		out, err := Unmarshal(b)
+		if err != nil {
+			t.Errorf("unexpected error %s on test %d", err, i)
+		}
+		if !reflect.DeepEqual(out, g) {
+			t.Errorf("incorrect value returned on test %d: %#v", i, out)
+		}
+	}
+}
+
+func Test_unmarshalIDs(t *testing.T) {
+	g := orb.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {}}
+	b, err := Marshal(g, WithIDs([]int64{12, 13}))
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	out, ids, err := UnmarshalIDs(b)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if !reflect.DeepEqual(out, g) || !reflect.DeepEqual(ids, []int64{12, 13}) {
+		t.Errorf("incorrect value returned %v %v", out, ids)
+	}
+}
+
+func Test_unmarshalZ(t *testing.T) {
+	// POINT Z (1 2 3) with a Z precision of 0
+	b, _ := hex.DecodeString("010801020406")
+	out, err := Unmarshal(b)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if out != (orb.Point{1, 2}) {
+		t.Errorf("incorrect value returned %v", out)
+	}
+}
+
+func Test_invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"01",
+		"0100",
+		"010002",
+		"0200ff",
+		"0100020400",
+		"0900",
+	}
+
+	for i, str := range inputs {
+		b, _ := hex.DecodeString(str)
+		if _, err := Unmarshal(b); err == nil {
+			t.Errorf("expected an error on test %d", i)
+		}
+	}
+	if _, err := Marshal(orb.Point{}, Precision(9)); err == nil {
+		t.Errorf("expected an error for the precision")
+	}
+	if _, err := Marshal(orb.MultiPoint{{1, 2}}, WithIDs([]int64{1, 2})); err == nil {
+		t.Errorf("expected an error for the ids")
+	}
+	for i, g := range []orb.Geometry{orb.Point{1, 2}, orb.LineString{{1, 2}, {3, 4}}, orb.Polygon{}} {
+		if _, err := Marshal(g, WithIDs([]int64{5, 6})); err == nil {
+			t.Errorf("expected an error for the ids of a single geometry on test %d", i)
+		}
+	}
+}
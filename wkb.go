@@ -0,0 +1,257 @@
+package wkttoorb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+)
+
+// EWKB type flags
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// decodeWKB decodes the WKB or EWKB geometry making up the whole of data
+// the ISO and EWKB Z and M values are skipped
+func decodeWKB(data []byte) (orb.Geometry, int, error) {
+	r := wkbReader{r: bytes.NewReader(data)}
+	class, srid, err := r.header()
+	var g orb.Geometry
+	if err == nil {
+		g, err = r.geometry(class)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, 0, ewkb.ErrNotEWKB
+	} else if err != nil {
+		return nil, 0, err
+	}
+	if r.r.Len() > 0 {
+		return nil, 0, fmt.Errorf("%d bytes after the geometry", r.r.Len())
+	}
+	return g, srid, nil
+}
+
+// wkbReader reads the geometry of a WKB, EWKB or SpatiaLite blob
+type wkbReader struct {
+	r     *bytes.Reader
+	order binary.ByteOrder
+	// spatiaLite is true if the members are SpatiaLite entities
+	// instead of WKB geometries with their own byte order
+	spatiaLite bool
+	// dims is the number of values of the coordinates of the current geometry
+	dims int
+}
+
+// header reads the byte order and the type of a WKB geometry
+// and returns the type as an ISO class, the EWKB flags are converted
+func (r *wkbReader) header() (uint32, int, error) {
+	order, err := r.r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	switch order {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return 0, 0, fmt.Errorf("invalid byte order %d", order)
+	}
+	typ, err := r.uint32()
+	if err != nil {
+		return 0, 0, err
+	}
+	var srid int
+	if typ&ewkbSRID != 0 {
+		v, err := r.uint32()
+		if err != nil {
+			return 0, 0, err
+		}
+		srid = int(int32(v))
+	}
+	class := typ &^ (ewkbZ | ewkbM | ewkbSRID)
+	if class != typ&^ewkbSRID {
+		if class >= 1000 {
+			return 0, 0, fmt.Errorf("unsupported geometry type 0x%x", typ)
+		}
+		if typ&ewkbZ != 0 {
+			class += 1000
+		}
+		if typ&ewkbM != 0 {
+			class += 2000
+		}
+	}
+	return class, srid, nil
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r.r, b[:]); err != nil {
+		return 0, err
+	}
+	return r.order.Uint32(b[:]), nil
+}
+
+// count reads the number of elements of size bytes at least that follow
+func (r *wkbReader) count(size int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(size) > uint64(r.r.Len()) {
+		return 0, fmt.Errorf("count %d larger than the input", n)
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) point() (orb.Point, error) {
+	var p orb.Point
+	var b [8]byte
+	for i := 0; i < r.dims; i++ {
+		if _, err := io.ReadFull(r.r, b[:]); err != nil {
+			return p, err
+		}
+		if i < 2 {
+			p[i] = math.Float64frombits(r.order.Uint64(b[:]))
+		}
+	}
+	return p, nil
+}
+
+func (r *wkbReader) points() ([]orb.Point, error) {
+	n, err := r.count(8 * r.dims)
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]orb.Point, n)
+	for i := range ps {
+		if ps[i], err = r.point(); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+func (r *wkbReader) polygon() (orb.Polygon, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return nil, err
+	}
+	p := make(orb.Polygon, n)
+	for i := range p {
+		ps, err := r.points()
+		if err != nil {
+			return nil, err
+		}
+		p[i] = orb.Ring(ps)
+	}
+	return p, nil
+}
+
+// member reads a member of a multi geometry or collection
+func (r *wkbReader) member() (orb.Geometry, error) {
+	if !r.spatiaLite {
+		class, _, err := r.header()
+		if err != nil {
+			return nil, err
+		}
+		return r.geometry(class)
+	}
+	marker, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if marker != spatiaLiteEntity {
+		return nil, fmt.Errorf("invalid entity marker 0x%x", marker)
+	}
+	class, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	return r.geometry(class)
+}
+
+// geometry reads a geometry of the given class, the WKB type with
+// 1000, 2000 or 3000 added for the Z, M and ZM variants
+func (r *wkbReader) geometry(class uint32) (orb.Geometry, error) {
+	if class > 3007 {
+		return nil, fmt.Errorf("unsupported geometry class %d", class)
+	}
+	r.dims = []int{2, 3, 3, 4}[class/1000]
+
+	switch class % 1000 {
+	case 1:
+		return r.point()
+	case 2:
+		ps, err := r.points()
+		return orb.LineString(ps), err
+	case 3:
+		return r.polygon()
+	}
+
+	n, err := r.count(5)
+	if err != nil {
+		return nil, err
+	}
+	switch class % 1000 {
+	case 4:
+		mp := make(orb.MultiPoint, n)
+		for i := range mp {
+			m, err := r.member()
+			if err != nil {
+				return nil, err
+			}
+			p, ok := m.(orb.Point)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s in multipoint", m.GeoJSONType())
+			}
+			mp[i] = p
+		}
+		return mp, nil
+	case 5:
+		mls := make(orb.MultiLineString, n)
+		for i := range mls {
+			m, err := r.member()
+			if err != nil {
+				return nil, err
+			}
+			ls, ok := m.(orb.LineString)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s in multilinestring", m.GeoJSONType())
+			}
+			mls[i] = ls
+		}
+		return mls, nil
+	case 6:
+		mp := make(orb.MultiPolygon, n)
+		for i := range mp {
+			m, err := r.member()
+			if err != nil {
+				return nil, err
+			}
+			p, ok := m.(orb.Polygon)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s in multipolygon", m.GeoJSONType())
+			}
+			mp[i] = p
+		}
+		return mp, nil
+	case 7:
+		c := make(orb.Collection, n)
+		for i := range c {
+			if c[i], err = r.member(); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry class %d", class)
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/paulmach/orb"
 )
 
-func Scan(s string) (orb.Geometry, error) {
-	p := Parser{NewLexer(strings.NewReader(s))}
+// Scan parses the WKT string s into an orb geometry
+func Scan(s string, opts ...Option) (orb.Geometry, error) {
+	p := NewParser(strings.NewReader(s), opts...)
 	return p.Parse()
 }